@@ -0,0 +1,16 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnAll registers a new consumer like On, which receives every value emitted
+// through e, whatever type it was emitted as. This differs from On[any],
+// which only receives values emitted as any. Consumers registered with
+// OnAll are called after consumers of the emitted type, are not called
+// by EmitOne and are not counted by Count or HasSubscribers.
+func OnAll(e *Emitter, fn func(any)) (off func() <-chan struct{}) {
+	return cm.OnAll(e, func(_ context.Context, v any) { fn(v) })
+}
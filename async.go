@@ -0,0 +1,26 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// EmitAsync pushes value v to all consumers of type T, each in its own
+// goroutine, and returns a chan which will get closed once all of them
+// return. Consumers are free to call On or off without deadlocking.
+//
+// Receive order is undefined. A panicking consumer is recovered so it
+// doesn't take down other consumers, and reported to the panic handler
+// if one is set. Unlike Emit, EmitAsync doesn't panic if T has more
+// consumers than SetMaxFanout allows, but drops v.
+func EmitAsync[T any](e *Emitter, v T) <-chan struct{} {
+	return cm.EmitAsync(e, context.Background(), v)
+}
+
+// EmitPool works like EmitAsync, but instead of starting a goroutine
+// for each consumer, it spreads them across given number of workers.
+// Using workers <= 0 will use GOMAXPROCS workers instead.
+func EmitPool[T any](e *Emitter, v T, workers int) <-chan struct{} {
+	return cm.EmitPool(e, context.Background(), v, workers)
+}
@@ -0,0 +1,27 @@
+package mint
+
+import (
+	"context"
+	"runtime"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// SetCaptureCaller makes emits through e record where they were called
+// from, which consumers registered with OnDebug receive. It is meant for
+// debugging, as walking the stack makes every emit considerably slower.
+// Values queued by Pause or a serial Emitter report where they were
+// delivered from instead.
+func SetCaptureCaller(e *Emitter, on bool) {
+	cm.SetCaptureCaller(e, on)
+}
+
+// OnDebug registers a new consumer like On, which also receives the frame
+// of the function which emitted v. It is the zero Frame unless e captures
+// callers.
+func OnDebug[T any](e *Emitter, fn func(v T, emittedFrom runtime.Frame)) (off func() <-chan struct{}) {
+	return cm.On(e, func(ctx context.Context, v T) {
+		frame, _ := cm.Caller(ctx)
+		fn(v, frame)
+	})
+}
@@ -0,0 +1,17 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// EmitCancelable works like Emit, but pushes v from a new goroutine and
+// returns right away. Calling cancel stops it from calling consumers
+// which weren't called yet, while wait blocks until the emit is done
+// and returns context.Canceled if cancel stopped it, ErrClosed if e is
+// closed, or an error returned by a guard plugin. Both may be called
+// more than once, from any goroutine.
+func EmitCancelable[T any](e *Emitter, v T) (wait func() error, cancel func()) {
+	return cm.EmitCancelable(e, context.Background(), v)
+}
@@ -0,0 +1,40 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// ErrClosed is returned when emitting with a closed Emitter.
+var ErrClosed = cm.ErrClosed
+
+// Close unsubscribes all consumers, drops all retained values and marks
+// the Emitter as closed. Emitting with a closed Emitter does nothing,
+// while new consumers are never subscribed and their off returns
+// a closed chan. Emits which are already in progress may still
+// deliver values to removed consumers.
+// Call Drain after Close to wait for them.
+//
+// Consumers which own a goroutine or chan are removed as if with their
+// off, so that nothing outlives e: chans of OnChan and OnChanErr get
+// closed, goroutines of OnCtx and OnQueue exit, and values pending in
+// OnDebounce and OnThrottle are dropped.
+//
+// Closing an already closed Emitter returns ErrClosed.
+func Close(e *Emitter) error {
+	return cm.Close(e)
+}
+
+// BindContext ties lifetime of e to ctx: once ctx is cancelled, e is
+// closed as if with Close, and deliveries which outlive their Emits are
+// waited for as if with Drain. Emits after that do nothing, as with any
+// closed Emitter. If e is closed before ctx is cancelled, BindContext
+// gives up on it.
+//
+// Returned chan gets closed once e is closed and drained after ctx is
+// cancelled, or once BindContext gave up on it, so that it can be waited
+// for on shutdown.
+func BindContext(e *Emitter, ctx context.Context) (done <-chan struct{}) {
+	return cm.BindContext(e, ctx)
+}
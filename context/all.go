@@ -0,0 +1,35 @@
+package mint
+
+import "context"
+
+// OnAll registers a new consumer like On, which receives every value emitted
+// through e, whatever type it was emitted as. This differs from On[any],
+// which only receives values emitted as any. Consumers registered with
+// OnAll are called after consumers of the emitted type, are not called
+// by EmitOne and are not counted by Count or HasSubscribers.
+func OnAll(e *Emitter, fn func(context.Context, any)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	call := func(ctx context.Context, v any) error {
+		fn(ctx, v)
+		return nil
+	}
+	off, _, _ = register(e, nil, 0, "", call, call, nil)
+	return off
+}
+
+// all lists consumers in b like each, followed by consumers of all types.
+// Caller must hold e.mu.
+func all(e *Emitter, b *bucket) []*sub {
+	subs := each(e, b)
+	if every := e.subs[nil]; every.len() > 0 {
+		// subs may be listed by b, so it must not be appended to in place
+		subs = append(subs[:len(subs):len(subs)], each(e, every)...)
+	}
+	return subs
+}
@@ -0,0 +1,210 @@
+package mint
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EmitAsync pushes value v to all consumers of type T, each in its own
+// goroutine, and returns a chan which will get closed once all of them
+// return. Consumers are snapshotted before any of them is started, so
+// they are free to call On or off without deadlocking.
+//
+// Plugins are called before consumers are started and functions they
+// return are called after the last consumer returns. No consumers are
+// started if a guard plugin stops the emit.
+//
+// Receive order is undefined. A panicking consumer is recovered so it
+// doesn't take down other consumers, and reported to the panic handler
+// if one is set. Panics of the handler itself are discarded. Consumers
+// are not started once ctx is cancelled.
+//
+// Using nil context will use context.Background() instead.
+func EmitAsync[T any](e *Emitter, ctx context.Context, v T) <-chan struct{} {
+	return emitAsync(e, ctx, v, func(_ error, n int, run func(i int) error, finish func()) {
+		go func() {
+			defer finish()
+
+			var wg sync.WaitGroup
+			wg.Add(n)
+			for i := 0; i < n; i++ {
+				go func(i int) {
+					defer wg.Done()
+					_ = run(i)
+				}(i)
+			}
+			wg.Wait()
+		}()
+	})
+}
+
+// EmitPool works like EmitAsync, but instead of starting a goroutine
+// for each consumer, it spreads them across given number of workers.
+// Using workers <= 0 will use GOMAXPROCS workers instead.
+func EmitPool[T any](e *Emitter, ctx context.Context, v T, workers int) <-chan struct{} {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return emitAsync(e, ctx, v, func(_ error, n int, run func(i int) error, finish func()) {
+		go func() {
+			defer finish()
+
+			if workers > n {
+				workers = n
+			}
+
+			jobs := make(chan int)
+			var wg sync.WaitGroup
+			wg.Add(workers)
+			for i := 0; i < workers; i++ {
+				go func() {
+					defer wg.Done()
+					for i := range jobs {
+						_ = run(i)
+					}
+				}()
+			}
+
+			for i := 0; i < n; i++ {
+				jobs <- i
+			}
+			close(jobs)
+			wg.Wait()
+		}()
+	})
+}
+
+// emitAsync snapshots consumers of T, calls plugins and hands consumers
+// to dispatch. dispatch must call run with index of each of n consumers
+// and call finish once all runs return, without waiting for them itself.
+// If the emit was stopped, dispatch gets why and no consumers.
+func emitAsync[T any](e *Emitter, ctx context.Context, v T,
+	dispatch func(err error, n int, run func(i int) error, finish func()),
+) <-chan struct{} {
+	done := make(chan struct{})
+	if e == nil {
+		close(done)
+		return done
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if e.held() && e.hold(func() { <-emitAsyncNow(e, ctx, v, dispatch, done) }) {
+		return done
+	}
+	return emitAsyncNow(e, ctx, v, dispatch, done)
+}
+
+// emitAsyncNow works like emitAsync with non-nil e and ctx, but ignores
+// Pause, closing done once dispatch returns.
+func emitAsyncNow[T any](e *Emitter, ctx context.Context, v T,
+	dispatch func(err error, n int, run func(i int) error, finish func()),
+	done chan struct{},
+) <-chan struct{} {
+	ctx, s, err := snap(e, ctx, typeOf[T](), all, typed[T])
+	if err == nil && s.discard {
+		_, err = deliver(ctx, v, &s, nil, nil)
+	}
+	if err != nil || s.discard {
+		dispatch(err, 0, nil, func() { close(done) })
+		return done
+	}
+	if s.hist != nil {
+		// in case plugins stop the emit or panic
+		defer s.hist.done(s.ticket)
+	}
+
+	var called atomic.Int32
+	name, start := "", time.Now()
+	if s.metrics != nil {
+		name = s.typeName()
+		s.metrics.EmitStarted(name)
+	}
+
+	// plugins may panic, in which case the emit is over before it
+	// is counted in flight, and metrics have to be told it is done
+	plugged := false
+	defer func() {
+		if !plugged && s.metrics != nil {
+			s.metrics.EmitFinished(name, time.Since(start), 0)
+		}
+	}()
+
+	ctx = number(ctx, s.seq)
+	ctx, v, afters, err := plug(ctx, v, s.typ, s.plugins)
+	plugged = true
+	e.flight.add(1)
+	if err != nil {
+		s.subs = nil
+	} else {
+		if s.hist != nil {
+			s.hist.add(v, s.ticket)
+		}
+		tapAll(s.taps, s.typ, v)
+	}
+	if s.hist != nil {
+		s.hist.done(s.ticket)
+	}
+
+	// errors of consumers, collected only for plugins
+	var errMu sync.Mutex
+	var errs []error
+
+	run := func(i int) (err error) {
+		if ctx.Err() != nil || s.from[i].removed.Load() {
+			return nil
+		}
+
+		called.Add(1)
+		if s.stats {
+			start := time.Now()
+			defer func() { s.count(i, time.Since(start)) }()
+		}
+		ctx := ctx
+		if s.isolated {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			defer cancel()
+		}
+		defer func() {
+			r := recover()
+			if r == nil || s.onPanic == nil {
+				return
+			}
+			defer func() { _ = recover() }()
+			s.onPanic(s.consumer(i), r, v)
+		}()
+		if err := s.subs[i](ctx, v); err != errStop {
+			if err != nil && afters != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+			}
+			return err
+		}
+		return nil
+	}
+
+	dispatch(err, len(s.subs), run, func() {
+		if err == nil && len(s.streams) > 0 {
+			stream(ctx, s.streams, s.onPanic, any(v))
+		}
+		if afters != nil {
+			unwind(afters, errors.Join(append(errs, err, ctx.Err())...))
+		}
+		if s.metrics != nil {
+			s.metrics.EmitFinished(name, time.Since(start), int(called.Load()))
+		}
+		e.flight.add(-1)
+		close(done)
+	})
+
+	return done
+}
@@ -0,0 +1,52 @@
+package mint
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+// callerKey is the context key of the frame an emit was called from.
+type callerKey struct{}
+
+// SetCaptureCaller makes emits through e record where they were called
+// from, which consumers and plugins can get with Caller. It is meant for
+// debugging, as walking the stack makes every emit considerably slower.
+// Values queued by Pause or a serial Emitter report where they were
+// delivered from instead.
+func SetCaptureCaller(e *Emitter, on bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.captureCaller = on
+}
+
+// Caller returns the frame of the function which called Emit, or one of
+// its variants, with ctx, as recorded by an Emitter which captures callers.
+// It reports whether ctx carries one.
+func Caller(ctx context.Context) (frame runtime.Frame, ok bool) {
+	frame, ok = ctx.Value(callerKey{}).(runtime.Frame)
+	return frame, ok
+}
+
+// capture returns context carrying the frame of the first caller
+// outside of mint, or ctx itself if there is none.
+func capture(ctx context.Context) context.Context {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !internal(f.Function) {
+			return context.WithValue(ctx, callerKey{}, f)
+		}
+		if !more {
+			return ctx
+		}
+	}
+}
+
+// internal reports whether fn is a function of mint or mint/context.
+func internal(fn string) bool {
+	rest, ok := strings.CutPrefix(fn, "github.com/btvoidx/mint")
+	return ok && (strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "/context."))
+}
@@ -0,0 +1,28 @@
+package mint
+
+import "context"
+
+// EmitCancelable works like Emit, but pushes v from a new goroutine and
+// returns right away. Calling cancel stops it from calling consumers
+// which weren't called yet, as if ctx was cancelled, while wait blocks
+// until the emit is done and returns what Emit would have, which is
+// context.Canceled if cancel stopped it. Both may be called more than
+// once, from any goroutine.
+func EmitCancelable[T any](e *Emitter, ctx context.Context, v T) (wait func() error, cancel func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel = context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		err = Emit(e, ctx, v)
+	}()
+
+	return func() error {
+		<-done
+		return err
+	}, cancel
+}
@@ -0,0 +1,112 @@
+package mint
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClosed is returned when emitting with a closed Emitter.
+var ErrClosed = errors.New("mint: emitter is closed")
+
+// Close unsubscribes all consumers, drops all retained values and marks
+// the Emitter as closed. Emitting with a closed Emitter does nothing and
+// returns ErrClosed, while new consumers are never subscribed and their
+// off returns a closed chan. Emits which are already in progress may
+// still deliver values to removed consumers.
+// Call Drain after Close to wait for them.
+//
+// Consumers which own a goroutine or chan are removed as if with their
+// off, so that nothing outlives e: chans of OnChan and OnChanErr get
+// closed, goroutines of OnCtx and OnQueue exit, and values pending in
+// OnDebounce and OnThrottle are dropped.
+//
+// Closing an already closed Emitter returns ErrClosed.
+func Close(e *Emitter) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return ErrClosed
+	}
+
+	e.closed = true
+	if e.closing != nil {
+		close(e.closing)
+	}
+	e.subs = nil
+	e.streams = nil
+	e.retained = nil
+	if e.deadLetter != nil {
+		close(e.deadLetter.quit)
+		e.deadLetter = nil
+	}
+
+	var dropped []func()
+	if e.serial != nil {
+		e.queueMu.Lock()
+		dropped = e.serial.stop(e)
+		e.queueMu.Unlock()
+	}
+	e.mu.Unlock()
+
+	// replays of dropped values see ErrClosed and only clean up
+	for _, replay := range dropped {
+		replay()
+		e.flight.add(-1)
+	}
+	return nil
+}
+
+// BindContext ties lifetime of e to ctx: once ctx is cancelled, e is
+// closed as if with Close, and deliveries which outlive their Emits are
+// waited for as if with Drain. Emits after that do nothing, as with any
+// closed Emitter. If e is closed before ctx is cancelled, BindContext
+// gives up on it.
+//
+// Returned chan gets closed once e is closed and drained after ctx is
+// cancelled, or once BindContext gave up on it, so that it can be waited
+// for on shutdown.
+func BindContext(e *Emitter, ctx context.Context) (done <-chan struct{}) {
+	if e == nil || ctx == nil || ctx.Done() == nil {
+		return closedChan
+	}
+
+	closing := e.closingChan()
+	if closing == closedChan {
+		return closedChan
+	}
+
+	c := make(chan struct{})
+	go func() {
+		defer close(c)
+		select {
+		case <-ctx.Done():
+			_ = Close(e)
+			_ = Drain(e, nil)
+		case <-closing:
+		}
+	}()
+	return c
+}
+
+// closingChan returns a chan which gets closed once e is closed, so that
+// goroutines of consumers can stop along with e.
+func (e *Emitter) closingChan() <-chan struct{} {
+	if e == nil {
+		return closedChan
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return closedChan
+	}
+	if e.closing == nil {
+		e.closing = make(chan struct{})
+	}
+	return e.closing
+}
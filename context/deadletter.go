@@ -0,0 +1,105 @@
+package mint
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// deadLetter passes dropped values to a handler from a goroutine
+// of its own, through a buffer of fixed size.
+type deadLetter struct {
+	letters chan letter
+	quit    chan struct{}
+	// values which didn't fit into the buffer
+	lost atomic.Uint64
+}
+
+// letter is a dropped value, along with name of the type it was emitted as.
+type letter struct {
+	typeName string
+	v        any
+}
+
+// SetDeadLetter makes Emitter pass values which were dropped instead of
+// being delivered to fn, along with the type they were emitted as, so
+// that they can be inspected. Values are dropped by buffered consumers
+// which are full, by Suppress and by SetMaxFanout. Using nil fn stops
+// passing them, which is the default.
+//
+// fn is called from a goroutine of its own, in order values were dropped,
+// and never holds up Emits: dropped values wait for it in a buffer of
+// given size, and once the buffer is full, further ones are discarded
+// and counted by DeadLetterLost. Sizes below 1 are treated as 1.
+// Setting another fn, as well as Close, stops the previous one once
+// values which are already buffered are passed to it.
+func SetDeadLetter(e *Emitter, size int, fn func(typeName string, v any)) {
+	if size < 1 {
+		size = 1
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.deadLetter != nil {
+		close(e.deadLetter.quit)
+		e.deadLetter = nil
+	}
+	if fn == nil {
+		return
+	}
+
+	d := &deadLetter{letters: make(chan letter, size), quit: make(chan struct{})}
+	go func() {
+		for {
+			select {
+			case l := <-d.letters:
+				fn(l.typeName, l.v)
+			case <-d.quit:
+				for {
+					select {
+					case l := <-d.letters:
+						fn(l.typeName, l.v)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+	e.deadLetter = d
+}
+
+// DeadLetterLost returns the number of dropped values which were
+// discarded by the current dead letter handler, as its buffer was full.
+func DeadLetterLost(e *Emitter) uint64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.deadLetter == nil {
+		return 0
+	}
+	return e.deadLetter.lost.Load()
+}
+
+// send buffers v, emitted as typeName, for the handler, if d is not nil,
+// without waiting for room in the buffer.
+func (d *deadLetter) send(typeName string, v any) {
+	if d == nil {
+		return
+	}
+
+	select {
+	case d.letters <- letter{typeName, v}:
+	default:
+		d.lost.Add(1)
+	}
+}
+
+// drop passes v, emitted as t, to the dead letter handler of e, if one is set.
+func drop(e *Emitter, t reflect.Type, v any) {
+	e.mu.RLock()
+	d := e.deadLetter
+	e.mu.RUnlock()
+
+	d.send(t.String(), v)
+}
@@ -0,0 +1,115 @@
+package mint
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OnDebounce registers a new consumer like On, but fn is only called once
+// emits of T stop for d, with the last value emitted before that. fn is
+// called from its own goroutine and receives context of the Emit of that
+// value, which may be cancelled by the time fn is called. Panics of fn are
+// reported to the panic handler of e, if one is set. Drain waits for
+// pending values to be passed to fn.
+//
+// Call to off unsubscribes, drops the pending value, if any, and returns
+// a chan which will get closed once fn returns, if it is running.
+// Closing e drops the pending value as well.
+func OnDebounce[T any](e *Emitter, d time.Duration, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	var mu sync.Mutex
+	var timer *time.Timer
+	var running sync.WaitGroup
+	var stopped, pending bool
+	var last struct {
+		ctx context.Context
+		v   T
+	}
+
+	var c Consumer
+	closing := e.closingChan()
+	fire := func() {
+		mu.Lock()
+		if stopped || !pending {
+			mu.Unlock()
+			return
+		}
+		pending = false
+		select {
+		case <-closing:
+			// e was closed since the value was emitted
+			last.ctx, last.v = nil, *new(T)
+			mu.Unlock()
+			e.flight.add(-1)
+			return
+		default:
+		}
+		ctx, v := last.ctx, last.v
+		last.ctx, last.v = nil, *new(T)
+		running.Add(1)
+		mu.Unlock()
+
+		defer running.Done()
+		defer e.flight.add(-1)
+
+		e.mu.RLock()
+		h := e.onPanic
+		e.mu.RUnlock()
+
+		_ = call(h, c, func(ctx context.Context, v T) error {
+			fn(ctx, v)
+			return nil
+		}, ctx, v)
+	}
+
+	e.mu.Lock()
+	stop, c, _ := subscribe(e, 0, "", func(ctx context.Context, v T) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return nil
+		}
+
+		last.ctx, last.v = ctx, v
+		if !pending {
+			pending = true
+			e.flight.add(1)
+		}
+		if timer == nil {
+			timer = time.AfterFunc(d, fire)
+		} else {
+			timer.Reset(d)
+		}
+		return nil
+	})
+	e.mu.Unlock()
+
+	done := make(chan struct{})
+	var once sync.Once
+	return func() <-chan struct{} {
+		once.Do(func() {
+			<-stop()
+
+			mu.Lock()
+			stopped = true
+			if timer != nil {
+				timer.Stop()
+			}
+			if pending {
+				pending = false
+				e.flight.add(-1)
+			}
+			mu.Unlock()
+
+			go func() {
+				running.Wait()
+				close(done)
+			}()
+		})
+		return done
+	}
+}
@@ -0,0 +1,46 @@
+package mint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrMaxDepthExceeded is returned by Emit when it is nested
+// in consumers of other Emits deeper than SetMaxDepth allows.
+var ErrMaxDepthExceeded = errors.New("mint: max emit depth exceeded")
+
+// depthKey is the context key of how deep an Emit of e is nested.
+type depthKey struct{ e *Emitter }
+
+// SetMaxDepth limits how deep Emits may be nested in consumers of other
+// Emits, which would otherwise allow cyclic consumers to emit forever.
+// An Emit nested deeper than n doesn't call anything and returns an error
+// wrapping ErrMaxDepthExceeded. Using n <= 0 removes the limit, which is
+// the default.
+//
+// SetMaxDepth only works with this package. Depth is carried by the
+// context consumers receive, so only Emits which are passed that context
+// (or one derived from it) are counted. Emits of the contextless mint
+// package always start with context.Background(), so cascades made
+// through it are never limited.
+func SetMaxDepth(e *Emitter, n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxDepth = n
+}
+
+// descend returns context for consumers of an Emit of type t, or an error
+// if the Emit is nested too deep. Caller must hold e.mu.
+func descend(e *Emitter, ctx context.Context, t reflect.Type) (context.Context, error) {
+	if e.maxDepth <= 0 {
+		return ctx, nil
+	}
+
+	depth, _ := ctx.Value(depthKey{e}).(int)
+	if depth >= e.maxDepth {
+		return ctx, fmt.Errorf("%w: emitting %v nested %d times", ErrMaxDepthExceeded, t, depth)
+	}
+	return context.WithValue(ctx, depthKey{e}, depth+1), nil
+}
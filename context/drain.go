@@ -0,0 +1,66 @@
+package mint
+
+import (
+	"context"
+	"sync"
+)
+
+// flight counts deliveries in progress.
+type flight struct {
+	mu   sync.Mutex
+	n    int
+	idle chan struct{} // closed once n drops to 0, nil if nobody waits
+}
+
+func (f *flight) add(delta int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.n += delta
+	if f.n == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// wait returns a chan which gets closed once no deliveries are in progress.
+func (f *flight) wait() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.n == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle
+	}
+
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	return f.idle
+}
+
+// Drain blocks until all deliveries which outlive their Emits are done,
+// which are ones started by EmitAsync, EmitPool and EmitGroup, and values queued
+// for consumers registered with OnQueue. If ctx is cancelled first,
+// ctx.Err() is returned. Using nil context will use context.Background().
+//
+// Drain doesn't stop new emits, which it waits for as well if they start
+// before it returns, so it should be paired with Close for shutdown.
+// Emits queued by Pause are not waited for until Resume.
+func Drain(e *Emitter, ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if e == nil {
+		return ctx.Err()
+	}
+
+	select {
+	case <-e.flight.wait():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,91 @@
+package mint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrTypeMismatch is returned by EmitDynamic when
+// the value can't be emitted as the given type.
+var ErrTypeMismatch = errors.New("mint: value does not match type")
+
+// EmitDynamic works like Emit, but pushes v to consumers of type t,
+// which is only known at runtime, so that EmitDynamic(e, ctx,
+// reflect.TypeOf(v), v) reaches consumers registered with On[T] if v is T.
+//
+// v must be assignable to t, otherwise nothing is called and an error
+// wrapping ErrTypeMismatch is returned.
+func EmitDynamic(e *Emitter, ctx context.Context, t reflect.Type, v any) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !assignable(v, t) {
+		return fmt.Errorf("%w: %T can't be emitted as %v", ErrTypeMismatch, v, t)
+	}
+
+	if e == nil {
+		return ctx.Err()
+	}
+
+	if e.held() && e.hold(func() { _ = emitDynamic(e, ctx, t, v) }) {
+		return nil
+	}
+	return emitDynamic(e, ctx, t, v)
+}
+
+// emitDynamic works like EmitDynamic with non-nil e and ctx
+// and v assignable to t, but ignores Pause.
+func emitDynamic(e *Emitter, ctx context.Context, t reflect.Type, v any) error {
+	ctx, s, err := snap(e, ctx, t, all, func(s *sub) func(context.Context, any) error { return s.dyn })
+	if err != nil {
+		return err
+	}
+
+	_, err = deliver(ctx, v, &s, nil, nil)
+	return err
+}
+
+// RawOn registers a new consumer like On, but of type t, which is only
+// known at runtime, for callers which can't use type parameters. t must
+// not be nil, otherwise RawOn panics.
+//
+// RawOn shares consumers with On, so that values emitted as T, whether
+// with Emit[T] or RawEmit with t of T, reach consumers registered with
+// On[T] and RawOn of t alike. fn receives them as any, holding T.
+func RawOn(e *Emitter, t reflect.Type, fn func(ctx context.Context, v any)) (off func() <-chan struct{}) {
+	if t == nil {
+		panic("mint: RawOn called with nil type")
+	}
+	if e == nil {
+		return noop
+	}
+
+	dyn := func(ctx context.Context, v any) error {
+		fn(ctx, v)
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	off, _, _ = register(e, t, 0, "", dyn, dyn, nil)
+	return off
+}
+
+// RawEmit is EmitDynamic, named to pair with RawOn.
+func RawEmit(e *Emitter, ctx context.Context, t reflect.Type, v any) error {
+	return EmitDynamic(e, ctx, t, v)
+}
+
+// assignable reports whether v can be emitted as t.
+func assignable(v any, t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if v == nil {
+		return t.Kind() == reflect.Interface
+	}
+	return reflect.TypeOf(v).AssignableTo(t)
+}
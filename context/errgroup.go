@@ -0,0 +1,54 @@
+package mint
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ErrGroup runs functions in goroutines and collects their errors.
+// It is implemented by *errgroup.Group of golang.org/x/sync/errgroup.
+type ErrGroup interface {
+	Go(fn func() error)
+}
+
+// EmitGroup works like EmitAsync, but runs each consumer with g, which
+// lets the caller limit concurrency and collect errors returned by
+// consumers registered with OnE. All of them are handed to g before
+// EmitGroup returns, so it is safe to wait for g right after.
+//
+// If the emit doesn't happen, such as when e is closed or a guard plugin
+// stops it, the reason is handed to g as well. EmitGroup is not affected
+// by Pause, as consumers have to be handed to g right away.
+func EmitGroup[T any](e *Emitter, ctx context.Context, g ErrGroup, v T) {
+	if e == nil {
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	emitAsyncNow(e, ctx, v, func(err error, n int, run func(i int) error, finish func()) {
+		if err != nil {
+			g.Go(func() error { return err })
+		}
+		if n == 0 {
+			finish()
+			return
+		}
+
+		var left atomic.Int32
+		left.Store(int32(n))
+		for i := 0; i < n; i++ {
+			i := i
+			g.Go(func() error {
+				defer func() {
+					if left.Add(-1) == 0 {
+						finish()
+					}
+				}()
+				return run(i)
+			})
+		}
+	}, make(chan struct{}))
+}
@@ -0,0 +1,94 @@
+package mint
+
+import (
+	"context"
+	"sync"
+)
+
+// streamer is a consumer registered with OnError. Such consumers are kept
+// apart from consumers of types, as they don't receive values of any
+// type of their own.
+type streamer struct {
+	id uint64
+	fn func(context.Context, error) error
+}
+
+// OnError registers a new consumer like On, which receives all non-nil values
+// implementing error that are emitted through e, whatever type they are
+// emitted as, so that failures can be observed in a single place.
+// Consumers of their own types still receive them as usual, and plugins
+// are not called again for the consumer. It is called after them, or once
+// all of them return for EmitAsync and its variants. It is not listed
+// by Consumers, Types and similar, as it isn't a consumer of a type.
+//
+// Values are only checked for implementing error while e has consumers
+// registered with OnError, so emits of other types don't pay for it.
+func OnError(e *Emitter, fn func(context.Context, error)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return noop
+	}
+
+	e.subc += 1
+	id := e.subc
+	// streamers are never modified in place, as emits
+	// keep using them after releasing the lock
+	streams := make([]streamer, 0, len(e.streams)+1)
+	streams = append(streams, e.streams...)
+	e.streams = append(streams, streamer{id: id, fn: func(ctx context.Context, err error) error {
+		fn(ctx, err)
+		return nil
+	}})
+
+	var once sync.Once
+	return func() <-chan struct{} {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+
+			streams := make([]streamer, 0, len(e.streams))
+			for _, s := range e.streams {
+				if s.id != id {
+					streams = append(streams, s)
+				}
+			}
+			e.streams = streams
+		})
+		return closedChan
+	}
+}
+
+// EmitError emits err as error, so that it reaches consumers of error
+// as well as ones registered with OnError. Emitting nil does nothing.
+func EmitError(e *Emitter, ctx context.Context, err error) error {
+	if err == nil {
+		if ctx == nil {
+			return nil
+		}
+		return ctx.Err()
+	}
+	return Emit(e, ctx, err)
+}
+
+// stream pushes v to streams, if v is a non-nil error. Panics of streams
+// are reported to h, if it is not nil. It is a part of the emit which
+// already went through plugins, so it doesn't count as an emit of its own.
+func stream(ctx context.Context, streams []streamer, h PanicHandler, v any) {
+	err, ok := v.(error)
+	if !ok || err == nil {
+		return
+	}
+
+	for _, s := range streams {
+		if ctx.Err() != nil {
+			return
+		}
+		_ = call(h, Consumer{ID: s.id, Type: typeOf[error]()}, s.fn, ctx, err)
+	}
+}
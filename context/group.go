@@ -0,0 +1,62 @@
+package mint
+
+import (
+	"context"
+	"sync"
+)
+
+// Group collects consumers of an Emitter, so that all of them can be
+// unsubscribed at once with Close. The zero Group is not usable,
+// use NewGroup instead.
+type Group struct {
+	e *Emitter
+
+	mu     sync.Mutex
+	offs   []func() <-chan struct{}
+	closed bool
+}
+
+// NewGroup returns an empty Group of consumers of e.
+func NewGroup(e *Emitter) *Group {
+	return &Group{e: e}
+}
+
+// OnGroup registers a new consumer like On and adds it to g.
+// Consumers added to a closed Group are unsubscribed right away.
+//
+// Call to off removes consumer before Close does, and returns
+// a chan which is already closed.
+func OnGroup[T any](g *Group, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	return g.Add(On(g.e, fn))
+}
+
+// Add adds off of a consumer registered in any other way to g, so that
+// Close calls it, and returns off. If g is already closed, off is called
+// right away.
+func (g *Group) Add(off func() <-chan struct{}) func() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		<-off()
+		return off
+	}
+	g.offs = append(g.offs, off)
+	return off
+}
+
+// Close unsubscribes all consumers of g and returns a chan which is
+// closed once all of them are removed. Closing a Group more than once
+// does nothing.
+func (g *Group) Close() <-chan struct{} {
+	g.mu.Lock()
+	offs := g.offs
+	g.offs = nil
+	g.closed = true
+	g.mu.Unlock()
+
+	for _, off := range offs {
+		<-off()
+	}
+	return closedChan
+}
@@ -0,0 +1,68 @@
+package mint
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// OnIface registers a new consumer like On, but I must be an interface
+// type, otherwise OnIface panics. Along with values emitted as I, the
+// consumer receives values pushed by EmitIface which implement I.
+func OnIface[I any](e *Emitter, fn func(context.Context, I)) (off func() <-chan struct{}) {
+	if t := typeOf[I](); t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("mint: OnIface called with %v, which is not an interface", t))
+	}
+	return On(e, fn)
+}
+
+// EmitIface works like Emit, but along with consumers of T, v is pushed
+// to consumers of all interface types which v implements, such as those
+// registered with OnIface. Plugins are called once, and consumers of
+// all types are called in a single pass, taking priorities and order
+// of registration into account as Emit does.
+//
+// Unlike Emit, EmitIface relies on reflection, checking v against every
+// interface type with consumers, so it is considerably slower.
+func EmitIface[T any](e *Emitter, ctx context.Context, v T) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if e == nil {
+		return ctx.Err()
+	}
+
+	if e.held() && e.hold(func() { _ = emitIface(e, ctx, v) }) {
+		return nil
+	}
+	return emitIface(e, ctx, v)
+}
+
+// emitIface works like EmitIface with non-nil e and ctx, but ignores Pause.
+func emitIface[T any](e *Emitter, ctx context.Context, v T) error {
+	t, dt := typeOf[T](), typeOf[T]()
+	if x := any(v); x != nil {
+		dt = reflect.TypeOf(x)
+	}
+
+	pick := func(e *Emitter, b *bucket) []*sub {
+		merged := &bucket{subs: make(map[uint64]*sub, b.len())}
+		for it, b := range e.subs {
+			if it == t || it != nil && it.Kind() == reflect.Interface && dt.AssignableTo(it) {
+				for id, s := range b.subs {
+					merged.subs[id] = s
+				}
+			}
+		}
+		return all(e, merged)
+	}
+
+	ctx, s, err := snap(e, ctx, t, pick, func(s *sub) func(context.Context, any) error { return s.dyn })
+	if err != nil {
+		return err
+	}
+
+	_, err = deliver[any](ctx, v, &s, nil, nil)
+	return err
+}
@@ -0,0 +1,201 @@
+package mint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Consumer describes a registered consumer for diagnostics.
+type Consumer struct {
+	// ID is unique within the Emitter and grows in order of registration,
+	// starting from 1.
+	ID uint64
+	// Type is the type consumer was registered for,
+	// or nil if it was registered with OnAll.
+	Type reflect.Type
+	// Name is the name consumer was registered with by OnNamed,
+	// or an empty string if it was registered otherwise.
+	Name string
+}
+
+// consumer describes i-th consumer of s, if s knows it.
+func (s *snapshot[T]) consumer(i int) Consumer {
+	if s.from == nil {
+		return Consumer{Type: s.typ}
+	}
+	sub := s.from[i]
+	return Consumer{ID: sub.id, Type: sub.typ, Name: sub.name}
+}
+
+// Handle refers to a registered consumer, for building
+// subscription managers on top of an Emitter.
+type Handle struct {
+	c   Consumer
+	off func() <-chan struct{}
+}
+
+// ID returns ID of the consumer, which is also reported by Consumers
+// and diagnostic handlers, or 0 if it couldn't be registered.
+func (h Handle) ID() uint64 { return h.c.ID }
+
+// Consumer describes the consumer.
+func (h Handle) Consumer() Consumer { return h.c }
+
+// Off unsubscribes the consumer like off returned by On does.
+// Off of zero Handle does nothing.
+func (h Handle) Off() <-chan struct{} {
+	if h.off == nil {
+		return noop()
+	}
+	return h.off()
+}
+
+// OnH registers a new consumer like On, but returns its Handle.
+func OnH[T any](e *Emitter, fn func(context.Context, T)) Handle {
+	if e == nil {
+		return Handle{}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	off, c, _ := subscribe(e, 0, "", func(ctx context.Context, v T) error {
+		fn(ctx, v)
+		return nil
+	})
+	return Handle{c: c, off: off}
+}
+
+// OnNamed registers a new consumer like On, but with given name,
+// which is reported to panic and slow consumer handlers, and listed
+// by Consumers, so that consumers can be told apart.
+func OnNamed[T any](e *Emitter, name string, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	off, _, _ = subscribe(e, 0, name, func(ctx context.Context, v T) error {
+		fn(ctx, v)
+		return nil
+	})
+	return off
+}
+
+// Consumers returns all currently registered consumers,
+// in order they were registered in.
+func Consumers(e *Emitter) []Consumer {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var cs []Consumer
+	for t, b := range e.subs {
+		for _, s := range b.subs {
+			cs = append(cs, Consumer{ID: s.id, Type: t, Name: s.name})
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].ID < cs[j].ID })
+	return cs
+}
+
+// Types returns all types which currently have consumers,
+// along with the number of consumers of each of them.
+// Consumers registered with OnAll are not counted.
+func Types(e *Emitter) map[reflect.Type]int {
+	types := make(map[reflect.Type]int)
+	if e == nil {
+		return types
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for t, b := range e.subs {
+		if t != nil {
+			types[t] = len(b.subs)
+		}
+	}
+	return types
+}
+
+// TypeNames returns names of all types which currently have consumers,
+// sorted. Names are taken once the first consumer of a type registers,
+// so listing them doesn't need reflection.
+func TypeNames(e *Emitter) []string {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var names []string
+	for t, b := range e.subs {
+		if t != nil && b.len() > 0 {
+			names = append(names, b.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Snapshot records how many consumers each type had, so that the
+// topology of an Emitter can be verified after it is set up again,
+// such as after a reload.
+type Snapshot map[reflect.Type]int
+
+// Export returns a Snapshot of consumers currently registered with e.
+// Consumers registered with OnAll are not counted.
+func Export(e *Emitter) Snapshot {
+	return Snapshot(Types(e))
+}
+
+// Missing returns types which have fewer consumers in e than they had
+// when s was exported, along with how many consumers each of them lacks.
+func (s Snapshot) Missing(e *Emitter) map[reflect.Type]int {
+	now := Types(e)
+	missing := make(map[reflect.Type]int)
+	for t, n := range s {
+		if now[t] < n {
+			missing[t] = n - now[t]
+		}
+	}
+	return missing
+}
+
+// ErrMixedPointers is returned by CheckPointers when both T and *T
+// have consumers.
+var ErrMixedPointers = errors.New("mint: both value and pointer types have consumers")
+
+// CheckPointers returns an error wrapping ErrMixedPointers if some type
+// T and *T both have consumers, which is likely a mistake, as values
+// emitted as one of them never reach consumers of the other. It is meant
+// to be called from tests once an Emitter is set up.
+func CheckPointers(e *Emitter) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var mixed []string
+	for t := range e.subs {
+		if t != nil && t.Kind() == reflect.Pointer && e.subs[t.Elem()].len() > 0 {
+			mixed = append(mixed, t.Elem().String())
+		}
+	}
+	if len(mixed) == 0 {
+		return nil
+	}
+
+	sort.Strings(mixed)
+	return fmt.Errorf("%w: %s", ErrMixedPointers, strings.Join(mixed, ", "))
+}
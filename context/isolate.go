@@ -0,0 +1,30 @@
+package mint
+
+import "context"
+
+// SetIsolated makes each consumer receive a context of its own, derived
+// from the one passed to Emit and cancelled once the consumer returns,
+// so that work it starts with that context doesn't outlive it and can't
+// be mistaken for work of other consumers. Cancelling context of the Emit
+// still cancels contexts of all consumers and stops the Emit.
+//
+// Isolation is disabled by default, in which case all consumers of an
+// Emit share its context.
+func SetIsolated(e *Emitter, isolated bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.isolated = isolated
+}
+
+// isolate wraps invoke so that each consumer is called with
+// a child of its context, which is cancelled once it returns.
+func isolate[T any](invoke invoker[T]) invoker[T] {
+	if invoke == nil {
+		invoke = call[T]
+	}
+	return func(h PanicHandler, c Consumer, fn func(context.Context, T) error, ctx context.Context, v T) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		return invoke(h, c, fn, ctx, v)
+	}
+}
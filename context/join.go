@@ -0,0 +1,43 @@
+package mint
+
+import "sync"
+
+// Join combines offs into a single off, which calls all of them and
+// returns a chan which will get closed once all of them are done.
+// Together with On it allows one handler to consume several types,
+// while each of them is still dispatched in a type-safe manner:
+//
+//	off := mint.Join(
+//		mint.On(e, func(ctx context.Context, v StartEvent) { log(v) }),
+//		mint.On(e, func(ctx context.Context, v StopEvent) { log(v) }),
+//	)
+func Join(offs ...func() <-chan struct{}) (off func() <-chan struct{}) {
+	done := make(chan struct{})
+	var once sync.Once
+	return func() <-chan struct{} {
+		once.Do(func() {
+			pending := make([]<-chan struct{}, 0, len(offs))
+			for _, off := range offs {
+				c := off()
+				select {
+				case <-c:
+				default:
+					pending = append(pending, c)
+				}
+			}
+
+			if len(pending) == 0 {
+				close(done)
+				return
+			}
+
+			go func() {
+				for _, c := range pending {
+					<-c
+				}
+				close(done)
+			}()
+		})
+		return done
+	}
+}
@@ -0,0 +1,76 @@
+package mint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrTooManySubscribers is returned by TryOn when T
+// already has as many consumers as SetMaxSubscribers allows.
+var ErrTooManySubscribers = errors.New("mint: too many subscribers")
+
+// SetMaxSubscribers limits the number of consumers of T to n, so that
+// consumers which are forgotten to be unsubscribed surface early. Once T
+// has n consumers, further On and its variants return off which does
+// nothing, while TryOn returns an error wrapping ErrTooManySubscribers.
+// Consumers which are already registered are kept. Using n < 0 removes
+// the limit, which is the default.
+func SetMaxSubscribers[T any](e *Emitter, n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if n < 0 {
+		delete(e.limits, typeOf[T]())
+		return
+	}
+
+	if e.limits == nil {
+		e.limits = make(map[reflect.Type]int)
+	}
+	e.limits[typeOf[T]()] = n
+}
+
+// TryOn registers a new consumer like On, but returns an error if it
+// can't be registered, which is ErrNilEmitter, ErrClosed or an error
+// wrapping ErrTooManySubscribers. Returned off does nothing in that case.
+func TryOn[T any](e *Emitter, fn func(context.Context, T)) (off func() <-chan struct{}, err error) {
+	if e == nil {
+		return noop, ErrNilEmitter
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	off, _, err = subscribe(e, 0, "", func(ctx context.Context, v T) error {
+		fn(ctx, v)
+		return nil
+	})
+	return off, err
+}
+
+// ErrFanout is returned by emits of types which have more consumers
+// than SetMaxFanout allows.
+var ErrFanout = errors.New("mint: emit fans out to too many consumers")
+
+// SetMaxFanout makes emits of types which have more than n consumers fail
+// with an error wrapping ErrFanout, without calling plugins or consumers,
+// as a guard against accidental broadcast storms. EmitAsync and EmitPool,
+// which can't return errors, drop such values, passing them to the dead
+// letter handler if one is set, while EmitGroup hands the error to its
+// group. Unlike SetMaxSubscribers, it applies to all types and doesn't
+// stop consumers from registering.
+// Using n <= 0 removes the limit, which is the default.
+func SetMaxFanout(e *Emitter, n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxFanout = n
+}
+
+// fanout checks whether emitting as t to subs is allowed. Caller must hold e.mu.
+func fanout(e *Emitter, t reflect.Type, subs []*sub) error {
+	if e.maxFanout > 0 && len(subs) > e.maxFanout {
+		return fmt.Errorf("%w: %v has %d consumers, at most %d allowed", ErrFanout, t, len(subs), e.maxFanout)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package mint
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Logger receives debug records of an Emitter. *slog.Logger
+// implements it, as do loggers with a similar Debug method.
+type Logger interface {
+	Debug(msg string, args ...any)
+}
+
+// SetLogger makes Emitter log at debug level when consumers are added
+// or removed, when emits start and finish, and when panics of consumers
+// are recovered by the panic handler. Records carry the type name along
+// with the number of consumers. l is called synchronously, sometimes
+// while holding e, so it must not use e.
+//
+// Using nil l stops logging, which is the default.
+func SetLogger(e *Emitter, l Logger) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logger = l
+}
+
+// logged is Metrics which logs emits to log before passing them on to m.
+type logged struct {
+	m   Metrics
+	log Logger
+	n   int // consumers in snapshot
+}
+
+func (l logged) EmitStarted(typeName string) {
+	l.log.Debug("mint: emit started", "type", typeName, "consumers", l.n)
+	if l.m != nil {
+		l.m.EmitStarted(typeName)
+	}
+}
+
+func (l logged) EmitFinished(typeName string, d time.Duration, consumers int) {
+	l.log.Debug("mint: emit finished", "type", typeName, "consumers", consumers, "duration", d)
+	if l.m != nil {
+		l.m.EmitFinished(typeName, d, consumers)
+	}
+}
+
+// recovering returns h which also logs panics to l.
+func recovering(l Logger, h PanicHandler) PanicHandler {
+	return func(c Consumer, r any, v any) {
+		l.Debug("mint: panic recovered", "type", c.Type.String(), "consumer", c.ID, "panic", r)
+		h(c, r, v)
+	}
+}
+
+// logSubs logs that a consumer of t, or all types if t is nil, was added or removed with msg,
+// if e has a logger. Caller must hold e.mu.
+func logSubs(e *Emitter, msg string, t reflect.Type, id uint64) {
+	if e.logger != nil {
+		e.logger.Debug(msg, "type", fmt.Sprint(t), "consumer", id, "consumers", e.subs[t].len())
+	}
+}
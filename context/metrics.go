@@ -0,0 +1,21 @@
+package mint
+
+import "time"
+
+// Metrics receives measurements of emits. Its methods are called
+// synchronously by Emit, so they should return quickly.
+type Metrics interface {
+	// EmitStarted is called when an Emit of type typeName starts.
+	EmitStarted(typeName string)
+	// EmitFinished is called when an Emit of type typeName returns,
+	// with time it took including plugins, and number of consumers called.
+	EmitFinished(typeName string, d time.Duration, consumers int)
+}
+
+// SetMetrics makes Emitter report emits to m. Using nil m
+// stops reporting, which is the default.
+func SetMetrics(e *Emitter, m Metrics) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = m
+}
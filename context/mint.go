@@ -7,65 +7,610 @@ package mint
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-type key[T any] struct{}
-
 // Emitter holds all active consumers and Emit hooks.
+// The zero Emitter is ready to use. An Emitter must not be copied,
+// so it should be passed around as *Emitter; go vet reports copies.
+//
+// A single lock guards the Emitter, which emits only hold while looking
+// consumers up, so consumers of one type can come and go without waiting
+// for emits of others to finish. Waiting On and off calls keep new emits
+// from taking the lock, so they take effect promptly even while values
+// are emitted continuously. As that lock is held so briefly, it isn't
+// split by type: slow emits of one type don't slow down On and off of
+// another, so there is little to gain from the extra bookkeeping.
 type Emitter struct {
 	subc    uint64
-	plugins []func(context.Context, any) func()
-	// map[mkey[T]{}]map[uint64]func(context.Context, T)
-	subs map[any]map[uint64]any
+	plugins []hook
+	taps    []tap
+	onPanic PanicHandler
+	metrics Metrics
+	subs    map[reflect.Type]*bucket
+	// for sticky types and ones with history
+	retained map[reflect.Type]*retained
+	// consumers are called in order of registration
+	ordered bool
+	// some consumer was registered with a non-zero priority
+	prioritized bool
+	closed      bool
+	// closed by Close, if not nil
+	closing  chan struct{}
+	maxDepth int
+	// maximum number of consumers of some types
+	limits map[reflect.Type]int
+	// maximum number of consumers an emit may call, if positive
+	maxFanout int
+	// emits of types with a positive count are discarded
+	suppressed map[reflect.Type]int
+	// receives values which were dropped, if not nil
+	deadLetter *deadLetter
+	// consumers registered with OnError
+	streams []streamer
+	// consumers taking longer than slowAfter are reported to onSlow
+	slowAfter time.Duration
+	onSlow    func(c Consumer, elapsed time.Duration)
+	// consumers count their calls and time spent in them
+	stats bool
+	// deliveries which outlive their Emit
+	flight flight
+	// consumers receive contexts of their own
+	isolated bool
+	// number of emits so far and whether consumers need it
+	seq       atomic.Uint64
+	sequenced bool
+	// emits are queued until Resume
+	paused  atomic.Bool
+	queue   []func()
+	queueMu sync.Mutex
+	// emits are delivered by a single goroutine, if not nil
+	serial   *serial
+	resumeMu sync.Mutex
+	// receives debug records, if not nil
+	logger Logger
+	// emits record where they were called from
+	captureCaller bool
 
 	mu sync.RWMutex
 }
 
+// NewOrderedEmitter creates an Emitter which calls consumers of
+// each type in order they were registered in, instead of an undefined one.
+// Order of remaining consumers is kept as others unsubscribe.
+func NewOrderedEmitter() *Emitter {
+	return &Emitter{ordered: true}
+}
+
 func (e *Emitter) init() {
 	if e.subs == nil {
-		e.subs = make(map[any]map[uint64]any)
+		e.subs = make(map[reflect.Type]*bucket)
+	}
+}
+
+// bucket holds consumers of a single type.
+type bucket struct {
+	subs map[uint64]*sub
+	// of the type, taken once the bucket is made, so that
+	// emits and diagnostics don't have to take it every time
+	name string
+	// subs as listed by each, rebuilt whenever they change,
+	// so that emits don't have to sort them
+	list []*sub
+	// list as []func(context.Context, T) error for emits of T,
+	// built by build, so that emits don't have to convert them
+	fns   any
+	build func([]*sub) any
+	turn  atomic.Uint64 // of EmitOne
+}
+
+func (b *bucket) len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.subs)
+}
+
+// relist rebuilds list and fns of b after its subs change.
+// Caller must hold e.mu.
+func (b *bucket) relist(e *Emitter) {
+	b.list = list(e, b)
+	if b.build != nil {
+		b.fns = b.build(b.list)
+	}
+}
+
+// fnsOf picks functions which take T out of subs, for bucket.build.
+func fnsOf[T any](subs []*sub) any {
+	fns := make([]func(context.Context, T) error, len(subs))
+	for i, s := range subs {
+		fns[i] = typed[T](s)
 	}
+	return fns
+}
+
+// sub is a registered consumer.
+type sub struct {
+	id   uint64
+	prio int
+	name string
+	typ  reflect.Type // registered for
+	fn   any          // func(context.Context, T) error
+	// fn taking any, for emits of types only known at runtime
+	dyn func(context.Context, any) error
+	// number of calls and time spent in them, if stats are enabled
+	calls atomic.Uint64
+	spent atomic.Int64
+	// set once unsubscribed, so that emits in progress skip it
+	removed atomic.Bool
 }
 
 // Emit Sequentially pushes value v to all consumers of type T.
 // Receive order is indetermenistic. Cancelling ctx waits
 // for active consumer to return and stops emitting further.
 //
+// If ctx has a deadline, consumers are called in their own goroutine
+// and Emit returns once ctx is done, leaving the active consumer
+// running, so that the deadline bounds the whole emit.
+//
+// Consumers are looked up once Emit starts and are called without
+// holding the Emitter, so they are free to call On or off.
+//
 // Using nil context will use context.Background() instead.
-// error is always ctx.Err()
+// error is ctx.Err(), an error returned by a guard plugin,
+// ErrClosed if the Emitter is closed, or an error wrapping ErrFanout
+// or ErrMaxDepthExceeded if the emit goes over SetMaxFanout or
+// SetMaxDepth.
 func Emit[T any](e *Emitter, ctx context.Context, v T) error {
+	_, err := emit(e, ctx, v, nil, nil)
+	return err
+}
+
+// ErrNilEmitter is returned by EmitStrict when called with nil Emitter.
+var ErrNilEmitter = errors.New("mint: emitter is nil")
+
+// EmitStrict works like Emit, but returns ErrNilEmitter if e is nil
+// instead of doing nothing, to catch emitters left uninitialized.
+func EmitStrict[T any](e *Emitter, ctx context.Context, v T) error {
+	if e == nil {
+		return ErrNilEmitter
+	}
+	return Emit(e, ctx, v)
+}
+
+// EmitN works like Emit, but also returns the number of consumers
+// which were called. If ctx is cancelled during the emit, only
+// consumers called before that are counted.
+func EmitN[T any](e *Emitter, ctx context.Context, v T) (int, error) {
+	return emit(e, ctx, v, nil, nil)
+}
+
+// EmitErr works like Emit, but collects all non-nil errors returned
+// by consumers registered with OnE. Returned error joins them
+// with ctx.Err() using errors.Join.
+func EmitErr[T any](e *Emitter, ctx context.Context, v T) error {
+	var errs []error
+	_, err := emit(e, ctx, v, nil, func(err error) bool {
+		errs = append(errs, err)
+		return false
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// EmitUntilErr works like Emit, but stops calling consumers as soon as
+// one registered with OnE returns a non-nil error, and returns it.
+// Otherwise it returns the same errors as Emit.
+//
+// Consumers with higher priority are called first, while ones with equal
+// priority are called in an undefined order unless the Emitter is
+// ordered, so which consumers are skipped is only defined for them.
+func EmitUntilErr[T any](e *Emitter, ctx context.Context, v T) error {
+	var first error
+	_, err := emit(e, ctx, v, nil, func(err error) bool {
+		first = err
+		return true
+	})
+	if first != nil {
+		return first
+	}
+	return err
+}
+
+// EmitAll works like Emit, but pushes all of vs in order, looking
+// consumers up only once. Each value is pushed to all consumers before
+// the next one is, and plugins are called for each value separately.
+// Cancelling ctx or a guard plugin stops emitting further values.
+func EmitAll[T any](e *Emitter, ctx context.Context, vs []T) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	if e == nil {
 		return ctx.Err()
 	}
 
+	if e.held() && e.hold(func() { _ = emitAll(e, ctx, vs) }) {
+		return nil
+	}
+	return emitAll(e, ctx, vs)
+}
+
+// emitAll works like EmitAll with non-nil e and ctx, but ignores Pause.
+func emitAll[T any](e *Emitter, ctx context.Context, vs []T) error {
+	ctx, s, err := snap(e, ctx, typeOf[T](), all, typed[T])
+	if err != nil {
+		return err
+	}
+	if s.discard {
+		for _, v := range vs {
+			_, err = deliver(ctx, v, &s, nil, nil)
+		}
+		return err
+	}
+	if s.hist != nil {
+		s.batch = true
+		defer s.hist.done(s.ticket)
+	}
+
+	for _, v := range vs {
+		if _, err := deliver(ctx, v, &s, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invoker calls a consumer fn, described by c, with v,
+// reporting its panics to h if it is not nil. Emits take
+// nil invoker to call consumers with call.
+type invoker[T any] func(h PanicHandler, c Consumer, fn func(context.Context, T) error, ctx context.Context, v T) error
+
+// emit pushes v to consumers of T, calling each of them with invoke,
+// and passes non-nil errors they return to report, if it is not nil,
+// stopping once it returns true.
+// It returns the number of consumers called.
+func emit[T any](e *Emitter, ctx context.Context, v T, invoke invoker[T], report func(error) (stop bool)) (int, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
+	if e == nil {
+		return 0, ctx.Err()
+	}
+
+	if e.held() && e.hold(func() { _, _ = emitNow(e, ctx, v, invoke, nil) }) {
+		return 0, nil
+	}
+	return emitNow(e, ctx, v, invoke, report)
+}
+
+// emitNow works like emit with non-nil e and ctx, but ignores Pause.
+func emitNow[T any](e *Emitter, ctx context.Context, v T, invoke invoker[T], report func(error) (stop bool)) (int, error) {
+	ctx, s, err := snap(e, ctx, typeOf[T](), all, typed[T])
+	if err != nil {
+		return 0, err
+	}
+	return deliver(ctx, v, &s, invoke, report)
+}
+
+// snapshot is what an emit of T needs from an Emitter. It is taken
+// under the lock, so that plugins and consumers can be called without
+// holding it, which allows them to subscribe or unsubscribe.
+type snapshot[T any] struct {
+	typ     reflect.Type // emitted as
+	name    string       // of typ, if known
+	plugins []hook
+	taps    []tap
+	subs    []func(context.Context, T) error
+	onPanic PanicHandler
+	metrics Metrics
+	hist    *retained
+	// open on hist until the value is added, unless batch is set,
+	// in which case the emit closes it once all values are added
+	ticket uint64
+	batch  bool
+	// consumers receive contexts of their own
+	isolated bool
+	// emit counter, if consumers need it
+	seq *atomic.Uint64
+	// consumers behind subs, for describing them
+	from      []*sub
+	slowAfter time.Duration
+	onSlow    func(c Consumer, elapsed time.Duration)
+	// consumers count their calls
+	stats bool
+	// values are passed to deadLetter, if it is not nil, instead of
+	// being delivered, and emits return discardErr
+	discard    bool
+	discardErr error
+	deadLetter *deadLetter
+	// consumers registered with OnError
+	streams []streamer
+}
+
+// typeName returns name of the type s is emitted as.
+func (s *snapshot[T]) typeName() string {
+	if s.name != "" {
+		return s.name
+	}
+	return s.typ.String()
+}
+
+// snap takes a snapshot of e for an emit of type t and returns context
+// for the emit, or an error if it shouldn't happen. If values should be
+// dropped instead, returned snapshot is marked to discard them. pick lists consumers
+// to call, like each does, and get picks function to call out of them.
+func snap[T any](e *Emitter, ctx context.Context, t reflect.Type,
+	pick func(*Emitter, *bucket) []*sub,
+	get func(*sub) func(context.Context, T) error,
+) (context.Context, snapshot[T], error) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	for _, fn := range e.plugins {
-		after := fn(ctx, v)
+	if e.closed {
+		return ctx, snapshot[T]{}, ErrClosed
+	}
+	if e.suppressed[t] > 0 {
+		return ctx, snapshot[T]{typ: t, discard: true, deadLetter: e.deadLetter}, nil
+	}
+
+	ctx, err := descend(e, ctx, t)
+	if err != nil {
+		return ctx, snapshot[T]{}, err
+	}
+	if e.captureCaller {
+		ctx = capture(ctx)
+	}
+
+	b := e.subs[t]
+	s := snapshot[T]{
+		typ:      t,
+		plugins:  e.plugins,
+		onPanic:  e.onPanic,
+		taps:     e.taps,
+		metrics:  e.metrics,
+		isolated: e.isolated,
+		stats:    e.stats,
+		seq:      e.sequence(),
+		hist:     e.retained[t],
+	}
+	if e.onSlow != nil {
+		s.slowAfter, s.onSlow = e.slowAfter, e.onSlow
+	}
+
+	subs := pick(e, b)
+	if err := fanout(e, t, subs); err != nil {
+		return ctx, snapshot[T]{typ: t, discard: true, discardErr: err, deadLetter: e.deadLetter}, nil
+	}
+	if fns, ok := b.cached().([]func(context.Context, T) error); ok && same(subs, b.list) {
+		s.subs = fns
+	} else if len(subs) > 0 {
+		s.subs = make([]func(context.Context, T) error, len(subs))
+		for i, sub := range subs {
+			s.subs[i] = get(sub)
+		}
+	}
+	s.from = subs
+	if b != nil {
+		s.name = b.name
+	}
+	s.streams = e.streams
+	if s.hist != nil {
+		s.ticket = s.hist.take()
+	}
+	if e.logger != nil {
+		s.metrics = logged{m: e.metrics, log: e.logger, n: len(subs)}
+		if e.onPanic != nil {
+			s.onPanic = recovering(e.logger, e.onPanic)
+		}
+	}
+	return ctx, s, nil
+}
+
+// cached returns fns of b, if b is not nil.
+func (b *bucket) cached() any {
+	if b == nil {
+		return nil
+	}
+	return b.fns
+}
+
+// same reports whether a and b are the same non-empty slice.
+func same(a, b []*sub) bool {
+	return len(a) > 0 && len(a) == len(b) && &a[0] == &b[0]
+}
+
+// typed picks function of s which takes T.
+func typed[T any](s *sub) func(context.Context, T) error {
+	if fn, ok := s.fn.(func(context.Context, T) error); ok {
+		return fn
+	}
+	// consumers of all types take any
+	return func(ctx context.Context, v T) error { return s.dyn(ctx, v) }
+}
+
+// deliver passes v through plugins and then to consumers of s,
+// calling each of them with invoke and passing non-nil errors they
+// return to report, if it is not nil. It returns the number of consumers called.
+func deliver[T any](ctx context.Context, v T, s *snapshot[T], invoke invoker[T], report func(error) (stop bool)) (called int, err error) {
+	if s.discard {
+		s.deadLetter.send(s.typeName(), v)
+		return 0, s.discardErr
+	}
+	if s.hist != nil && !s.batch {
+		// in case plugins stop the emit or panic
+		defer s.hist.done(s.ticket)
+	}
+
+	if m := s.metrics; m != nil {
+		name, start := s.typeName(), time.Now()
+		m.EmitStarted(name)
+		defer func() { m.EmitFinished(name, time.Since(start), called) }()
+	}
+
+	ctx = number(ctx, s.seq)
+	ctx, v, afters, err := plug(ctx, v, s.typ, s.plugins)
+	var errs []error
+	if len(afters) > 0 {
+		defer func() { unwind(afters, errors.Join(append(errs, err)...)) }()
+	}
+	if err != nil {
+		return 0, err
+	}
+	if s.hist != nil {
+		s.hist.add(v, s.ticket)
+		if !s.batch {
+			s.hist.done(s.ticket)
+		}
+	}
+	tapAll(s.taps, s.typ, v)
+
+	if invoke == nil && len(s.subs) > 0 {
+		if _, ok := ctx.Deadline(); ok {
+			invoke = deadline[T]
+		}
+	}
+	if s.isolated {
+		invoke = isolate(invoke)
+	}
+	for i, fn := range s.subs {
+		if ctx.Err() != nil {
+			break
+		}
+		if s.from[i].removed.Load() {
+			continue
+		}
+		called += 1
+		var start time.Time
+		if s.timing() {
+			start = time.Now()
+		}
+		var cerr error
+		if invoke != nil {
+			cerr = invoke(s.onPanic, s.consumer(i), fn, ctx, v)
+		} else {
+			cerr = call(s.onPanic, s.consumer(i), fn, ctx, v)
+		}
+		if s.timing() {
+			s.timed(i, time.Since(start))
+		}
+		if cerr == errStop {
+			break
+		}
+		if cerr != nil && afters != nil {
+			errs = append(errs, cerr)
+		}
+		if cerr != nil && report != nil && report(cerr) {
+			break
+		}
+	}
+
+	if len(s.streams) > 0 {
+		stream(ctx, s.streams, s.onPanic, any(v))
+	}
+	return called, ctx.Err()
+}
+
+// plug passes v, emitted as t, through plugins and returns context and value
+// for consumers, along with functions plugins returned, in order they were returned.
+func plug[T any](ctx context.Context, v T, t reflect.Type, plugins []hook) (context.Context, T, []func(error), error) {
+	if len(plugins) == 0 {
+		return ctx, v, nil, nil
+	}
+
+	var afters []func(error)
+	var x any = v
+	for _, p := range plugins {
+		if p.typ != nil && p.typ != t {
+			continue
+		}
+
+		var after func(error)
+		var err error
+		ctx, x, after, err = p.fn(ctx, x)
 		if after != nil {
-			defer after()
+			afters = append(afters, after)
+		}
+		if err != nil {
+			return ctx, v, afters, err
 		}
 	}
+	return ctx, as[T](x), afters, nil
+}
 
-	subs, ok := e.subs[key[T]{}]
-	if !ok {
-		return ctx.Err()
+// unwind calls afters with outcome of the emit in reverse order,
+// as if they were deferred.
+func unwind(afters []func(error), outcome error) {
+	for _, after := range afters {
+		defer after(outcome)
 	}
+}
 
-	for _, fn := range subs {
-		if err := ctx.Err(); err != nil {
-			return err
+// each lists consumers in b. Consumers with higher priority are listed
+// first, and equal ones are listed in order of registration if e is
+// ordered. Returned slice must not be modified. Caller must hold e.mu.
+func each(e *Emitter, b *bucket) []*sub {
+	if b.len() == 0 {
+		return nil
+	}
+	if b.list != nil {
+		return b.list
+	}
+	return list(e, b)
+}
+
+// list sorts consumers in b into a new slice, as described by each.
+func list(e *Emitter, b *bucket) []*sub {
+
+	subs := make([]*sub, 0, len(b.subs))
+	for _, s := range b.subs {
+		subs = append(subs, s)
+	}
+	if !e.ordered && !e.prioritized {
+		return subs
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].prio != subs[j].prio {
+			return subs[i].prio > subs[j].prio
 		}
-		fn.(func(context.Context, T))(ctx, v)
+		return e.ordered && subs[i].id < subs[j].id
+	})
+	return subs
+}
+
+// as converts x, that was passed through plugins, back to T.
+func as[T any](x any) T {
+	v, ok := x.(T)
+	// nil x fails to assert even when T is an interface
+	if !ok && (x != nil || any(v) != nil) {
+		panic(fmt.Sprintf("mint: plugin replaced %v with a value of type %T", typeOf[T](), x))
 	}
+	return v
+}
+
+// typeOf returns type T, even if it is an interface.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
 
-	return ctx.Err()
+// call calls fn with v. If h is not nil, a panic of fn is recovered
+// and reported to h, in which case call returns nil.
+func call[T any](h PanicHandler, c Consumer, fn func(context.Context, T) error, ctx context.Context, v T) error {
+	if h != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				h(c, r, v)
+			}
+		}()
+	}
+	return fn(ctx, v)
 }
 
 // On Registers a new consumer that receives all values which were
@@ -75,49 +620,543 @@ func Emit[T any](e *Emitter, ctx context.Context, v T) error {
 // Reliance on a certain value to be present in the context
 // signals that it should be included in T.
 //
-// Call to off schedules consumer to stop once all concurrent Emits stop
-// and returns a chan which will get closed once it is done.
-// It is possible for consumer to receive values after a call to stop if
-// other concurrent emits are ongoing.
+// Consumer is registered before On returns, so any Emit which starts
+// after that, including ones from other goroutines which synchronize
+// with the call, delivers values to it.
+//
+// Call to off removes consumer before returning and returns a chan which
+// is already closed. Each Emit works with consumers that were registered
+// when it started, but skips ones which were removed before it got to them,
+// so consumer may only receive values after a call to off from concurrent
+// emits which got to it just before.
+//
+// A consumer may call its own off to stop after the current value,
+// in which case it is gone before the next Emit starts.
+//
+// On with nil Emitter, as well as its variants, does nothing and returns
+// off which does nothing, matching Emit.
 func On[T any](e *Emitter, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	e.init()
+	return on(e, 0, func(ctx context.Context, v T) error {
+		fn(ctx, v)
+		return nil
+	})
+}
 
-	if _, ok := e.subs[key[T]{}]; !ok {
-		e.subs[key[T]{}] = make(map[uint64]any)
+// OnP registers a new consumer like On, but with given priority.
+// During Emit consumers with higher priority are called before those
+// with lower one. Consumers registered with On have priority of 0.
+//
+// Relative order of consumers with equal priority is undefined,
+// unless the Emitter is ordered.
+func OnP[T any](e *Emitter, priority int, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return on(e, priority, func(ctx context.Context, v T) error {
+		fn(ctx, v)
+		return nil
+	})
+}
+
+// OnFilter registers a new consumer like On, but fn is only
+// called with values for which pred returns true.
+func OnFilter[T any](e *Emitter, pred func(context.Context, T) bool, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	return On(e, func(ctx context.Context, v T) {
+		if pred(ctx, v) {
+			fn(ctx, v)
+		}
+	})
+}
+
+// OnDedup registers a new consumer like On, but fn is not called
+// with values equal to the last one it was called with. The first
+// value is always passed to fn. Concurrent emits are compared with
+// each other in order they reach the consumer.
+func OnDedup[T comparable](e *Emitter, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	var mu sync.Mutex
+	var last T
+	var seen bool
+	return OnFilter(e, func(_ context.Context, v T) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if seen && v == last {
+			return false
+		}
+		last, seen = v, true
+		return true
+	}, fn)
+}
+
+// OnE registers a new consumer like On, but fn is allowed to
+// return an error. Errors are collected by EmitErr and are
+// discarded by other Emit variants.
+func OnE[T any](e *Emitter, fn func(context.Context, T) error) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return on(e, 0, fn)
+}
+
+// OnCtx registers a new consumer like On, which unsubscribes once ctx is
+// done. Until then, a goroutine waits for ctx, which exits on ctx
+// being done, a call to off or e being closed. Using ctx which is never done, such as
+// context.Background(), doesn't start the goroutine.
+func OnCtx[T any](e *Emitter, ctx context.Context, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	stop := On(e, fn)
+	if ctx.Done() == nil {
+		return stop
+	}
+
+	quit := make(chan struct{})
+	var once sync.Once
+	off = func() <-chan struct{} {
+		once.Do(func() { close(quit) })
+		return stop()
+	}
+
+	closing := e.closingChan()
+	go func() {
+		select {
+		case <-ctx.Done():
+			off()
+		case <-closing:
+			off()
+		case <-quit:
+		}
+	}()
+	return off
+}
+
+// Once registers a new consumer that receives only the first value
+// emitted as T and unsubscribes afterwards. Only one of concurrent
+// Emits gets to call fn.
+//
+// Calling off before any value is emitted cancels the subscription.
+func Once[T any](e *Emitter, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	var fired atomic.Bool
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	off = on(e, 0, func(ctx context.Context, v T) error {
+		if fired.CompareAndSwap(false, true) {
+			off()
+			fn(ctx, v)
+		}
+		return nil
+	})
+	return off
+}
+
+// OnUntil registers a new consumer that receives values emitted as T
+// until fn returns true, unsubscribing afterwards. Calls to fn from
+// concurrent Emits are serialized, so it is never called again once
+// it returns true.
+//
+// Calling off before that cancels the subscription.
+func OnUntil[T any](e *Emitter, fn func(context.Context, T) (stop bool)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	var mu sync.Mutex
+	var stopped bool
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	off = on(e, 0, func(ctx context.Context, v T) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !stopped && fn(ctx, v) {
+			stopped = true
+			off()
+		}
+		return nil
+	})
+	return off
+}
+
+// Wait blocks until next value is emitted as T and returns it.
+// If ctx is cancelled first, zero value and ctx.Err() are returned.
+// Using nil context will use context.Background() instead.
+func Wait[T any](e *Emitter, ctx context.Context) (T, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ch := make(chan T, 1)
+	off := Once(e, func(_ context.Context, v T) { ch <- v })
+	defer off()
+
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Overflow tells a buffered consumer what to do with a value
+// when its buffer is full.
+type Overflow int
+
+const (
+	// DropNewest discards the value being delivered.
+	DropNewest Overflow = iota
+	// Block waits for the buffer to free up, stalling the Emit
+	// until then or until its context is cancelled.
+	Block
+	// DropOldest discards the oldest buffered value to make
+	// room for the value being delivered.
+	DropOldest
+)
+
+// offer puts v into c according to policy and reports whether it did,
+// along with the number of buffered values it dropped to make room.
+// Block gives up once quit, which may be nil, is closed or ctx is cancelled.
+// Values which are dropped, including v, are passed to lost, if it is not nil.
+func offer[T any](ctx context.Context, c chan T, v T, policy Overflow, quit <-chan struct{}, lost func(T)) (sent bool, dropped int) {
+	switch policy {
+	case Block:
+		select {
+		case c <- v:
+			return true, 0
+		case <-quit:
+		case <-ctx.Done():
+		}
+		if lost != nil {
+			lost(v)
+		}
+		return false, 0
+
+	case DropOldest:
+		for {
+			select {
+			case c <- v:
+				return true, dropped
+			default:
+			}
+
+			select {
+			case old := <-c:
+				dropped += 1
+				if lost != nil {
+					lost(old)
+				}
+			default:
+			}
+		}
+
+	default:
+		select {
+		case c <- v:
+			return true, 0
+		default:
+			if lost != nil {
+				lost(v)
+			}
+			return false, 0
+		}
+	}
+}
+
+// OnChan registers a new consumer that forwards all values emitted as T
+// to a chan with given buffer size. Once the buffer is full, policy decides
+// what happens to further values. Using DropOldest with unbuffered
+// chan stalls the Emit until ch is received from.
+//
+// Call to off unsubscribes and closes ch once emits which are already
+// sending to it are done, so that a reader receives every value Emit
+// handed to the consumer and then sees ch closed. Returned chan is
+// closed after ch is. With Block and DropOldest this requires ch to be
+// received from until it is closed, as such emits wait for the reader.
+// Closing e calls off as well.
+func OnChan[T any](e *Emitter, buffer int, policy Overflow) (ch <-chan T, off func() <-chan struct{}) {
+	return onChan[T](e, buffer, policy, nil)
+}
+
+// OnChanErr registers a new consumer like OnChan with DropNewest, but
+// every time a value is dropped because the buffer is full, a signal is
+// sent to overflow, so that slow readers can be noticed without blocking
+// Emit. Signals are coalesced, so overflow holds at most one of them
+// until it is received from, and it is never closed.
+func OnChanErr[T any](e *Emitter, buffer int) (ch <-chan T, overflow <-chan struct{}, off func() <-chan struct{}) {
+	o := make(chan struct{}, 1)
+	ch, off = onChan[T](e, buffer, DropNewest, func() {
+		select {
+		case o <- struct{}{}:
+		default:
+		}
+	})
+	return ch, o, off
+}
+
+// onChan works like OnChan, but also calls lost, if it is not nil,
+// whenever a value is dropped.
+func onChan[T any](e *Emitter, buffer int, policy Overflow, lost func()) (ch <-chan T, off func() <-chan struct{}) {
+	if e == nil {
+		c := make(chan T)
+		close(c)
+		return c, noop
+	}
+
+	c := make(chan T, buffer)
+	dead := func(v T) { drop(e, typeOf[T](), v) }
+
+	// closed is guarded by mu so that c is never closed mid-send
+	var mu sync.RWMutex
+	var closed bool
+
+	stop := On(e, func(ctx context.Context, v T) {
+		mu.RLock()
+		defer mu.RUnlock()
+		if closed {
+			return
+		}
+		sent, dropped := offer(ctx, c, v, policy, nil, dead)
+		if (!sent || dropped > 0) && lost != nil {
+			lost()
+		}
+	})
+
+	done := make(chan struct{})
+	quit := make(chan struct{})
+	var once sync.Once
+	off = func() <-chan struct{} {
+		once.Do(func() {
+			close(quit)
+			go func() {
+				<-stop()
+
+				mu.Lock()
+				closed = true
+				close(c)
+				mu.Unlock()
+
+				close(done)
+			}()
+		})
+		return done
+	}
+
+	// close c once e is closed
+	closing := e.closingChan()
+	go func() {
+		select {
+		case <-closing:
+			off()
+		case <-quit:
+		}
+	}()
+	return c, off
+}
+
+// on registers fn as a consumer of T with given priority. If fn can't
+// be registered, returned off does nothing. Caller must hold e.mu.
+func on[T any](e *Emitter, prio int, fn func(context.Context, T) error) (off func() <-chan struct{}) {
+	off, _, _ = subscribe(e, prio, "", fn)
+	return off
+}
+
+// subscribe works like on, but also takes name of the consumer and returns
+// it, or why fn couldn't be registered. Caller must hold e.mu.
+func subscribe[T any](e *Emitter, prio int, name string, fn func(context.Context, T) error) (off func() <-chan struct{}, c Consumer, err error) {
+	return register(e, typeOf[T](), prio, name, fn, func(ctx context.Context, x any) error {
+		return fn(ctx, as[T](x))
+	}, fnsOf[T])
+}
+
+// register adds a consumer of type t, or of all types if t is nil,
+// with fn taking t and dyn taking any. build, if not nil, is used as
+// bucket.build for t. Caller must hold e.mu.
+func register(e *Emitter, t reflect.Type, prio int, name string, fn any, dyn func(context.Context, any) error, build func([]*sub) any) (off func() <-chan struct{}, c Consumer, err error) {
+	if e.closed {
+		return noop, c, ErrClosed
+	}
+
+	b, ok := e.subs[t]
+	if n, limited := e.limits[t]; limited && b.len() >= n {
+		return noop, c, fmt.Errorf("%w: %v already has %d", ErrTooManySubscribers, t, b.len())
+	}
+
+	e.init()
+	if !ok {
+		b = &bucket{subs: make(map[uint64]*sub), build: build}
+		if t != nil {
+			b.name = t.String()
+		}
+		e.subs[t] = b
+	} else if b.build == nil {
+		// bucket was made by a consumer registered with RawOn
+		b.build = build
 	}
 
-	id := e.subc
 	e.subc += 1
-	e.subs[key[T]{}][id] = fn
+	id := e.subc
+	s := &sub{id: id, prio: prio, name: name, typ: t, fn: fn, dyn: dyn}
+	b.subs[id] = s
+	if prio != 0 {
+		e.prioritized = true
+	}
+	b.relist(e)
+	logSubs(e, "mint: consumer added", t, id)
 
 	done := make(chan struct{})
 	var once sync.Once
 	return func() <-chan struct{} {
-		go once.Do(func() {
+		once.Do(func() {
 			e.mu.Lock()
 			defer e.mu.Unlock()
 
-			delete(e.subs[key[T]{}], id)
-			if len(e.subs[key[T]{}]) == 0 {
-				delete(e.subs, key[T]{})
+			if b := e.subs[t]; b != nil && b.subs[id] == s {
+				s.removed.Store(true)
+				delete(b.subs, id)
+				b.relist(e)
+				if len(b.subs) == 0 {
+					delete(e.subs, t)
+				}
+				logSubs(e, "mint: consumer removed", t, id)
 			}
 
 			close(done)
 		})
 		return done
+	}, Consumer{ID: id, Type: t, Name: name}, nil
+}
+
+// noop is off of a consumer which was never registered.
+func noop() <-chan struct{} {
+	return closedChan
+}
+
+// closedChan is a chan which is always closed.
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// OffSync calls off and waits until it is done. As Emits don't hold
+// on to the Emitter while calling consumers, off removes the consumer
+// right away, without starting a goroutine. It waits only for other
+// calls to On, off and similar, so shutdown is never held up by slow
+// consumers and doesn't need a timeout.
+func OffSync(off func() <-chan struct{}) {
+	<-off()
+}
+
+// Count returns the number of consumers currently registered for T.
+func Count[T any](e *Emitter) int {
+	if e == nil {
+		return 0
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.subs[typeOf[T]()].len()
+}
+
+// HasSubscribers reports whether any consumers are currently registered
+// for T. Emits of T without consumers don't allocate, however plugins,
+// taps, metrics and history still see them, so callers may check it to
+// avoid building values nobody receives.
+func HasSubscribers[T any](e *Emitter) bool {
+	return Count[T](e) > 0
+}
+
+// Clear unsubscribes all consumers of all types and drops all retained
+// values. Emits which are already in progress may still deliver values
+// to removed consumers.
+func Clear(e *Emitter) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.subs = nil
+	e.streams = nil
+	for _, r := range e.retained {
+		r.drop()
+	}
+}
+
+// ClearType unsubscribes all consumers of T and drops values retained
+// for it. Emits which are already in progress may still deliver values
+// to removed consumers.
+func ClearType[T any](e *Emitter) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.subs, typeOf[T]())
+	if r, ok := e.retained[typeOf[T]()]; ok {
+		r.drop()
 	}
 }
 
-// Use allows to hook into event emitting process. Plugins are
-// called sequentially in order they were added to Emitter.
-// Plugin is a function that takes Emitted values and
-// returns nil or a function that will be called after
-// all consumers got the Emitted value. Returned functions
-// are called in reverse order via `defer` statement.
-func Use(e *Emitter, plugin func(context.Context, any) func()) {
+// OffType unsubscribes all consumers of T, except for the one with ID
+// except, if there is one, so that a consumer can take over a value by
+// removing the others. Use 0 to remove all of them. As with off, emits
+// which are already in progress skip removed consumers they didn't get
+// to yet, so it is safe to call from consumers. Unlike ClearType, values
+// retained for T are kept.
+func OffType[T any](e *Emitter, except uint64) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t := typeOf[T]()
+	b := e.subs[t]
+	if b.len() == 0 {
+		return
+	}
+	for id, s := range b.subs {
+		if id != except {
+			s.removed.Store(true)
+			delete(b.subs, id)
+			logSubs(e, "mint: consumer removed", t, id)
+		}
+	}
+	b.relist(e)
+	if len(b.subs) == 0 {
+		delete(e.subs, t)
+	}
+}
+
+// PanicHandler receives panics of consumers, along with
+// the consumer which panicked and the value it was called with.
+type PanicHandler func(c Consumer, recovered any, v any)
+
+// SetPanicHandler makes Emit recover consumers which panic and report
+// them along with recovered value and the emitted value v to h, after
+// which emitting continues with the next consumer. If h itself panics,
+// the panic propagates to the caller of Emit.
+//
+// Without a handler (or with nil h) panics are not recovered.
+func SetPanicHandler(e *Emitter, h PanicHandler) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	e.plugins = append(e.plugins, plugin)
+	e.onPanic = h
 }
@@ -0,0 +1,47 @@
+package mint
+
+import (
+	"context"
+	"sort"
+)
+
+// EmitOne works like Emit, but pushes v to only one consumer of T, so that
+// Emitter can hand out work to its consumers. Consumers take turns in
+// order they were registered in, regardless of their priority, with each
+// Emit picking the one after the consumer picked by the previous one.
+// It reports whether there was a consumer to push v to.
+// EmitOne is not affected by Pause, as it has to report it right away.
+func EmitOne[T any](e *Emitter, ctx context.Context, v T) (delivered bool, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if e == nil {
+		return false, ctx.Err()
+	}
+
+	ctx, s, err := snap(e, ctx, typeOf[T](), next, typed[T])
+	if err != nil {
+		return false, err
+	}
+
+	n, err := deliver(ctx, v, &s, nil, nil)
+	return n > 0, err
+}
+
+// next lists the consumer in b whose turn it is.
+// Caller must hold e.mu.
+func next(e *Emitter, b *bucket) []*sub {
+	if b.len() == 0 {
+		return nil
+	}
+
+	subs := make([]*sub, 0, len(b.subs))
+	for _, s := range b.subs {
+		subs = append(subs, s)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].id < subs[j].id })
+
+	turn := b.turn.Add(1) - 1
+	return subs[turn%uint64(len(subs)):][:1]
+}
@@ -0,0 +1,76 @@
+package mint
+
+// Pause makes following emits through e queue values instead of delivering
+// them, until Resume is called. Queued emits return right away as if there
+// were no consumers, except for EmitAsync and EmitPool, which return chans
+// that get closed once their values are delivered after Resume.
+//
+// Pausing a paused Emitter does nothing.
+func Pause(e *Emitter) {
+	e.queueMu.Lock()
+	defer e.queueMu.Unlock()
+	e.paused.Store(true)
+}
+
+// Resume delivers values queued since Pause in order they were emitted
+// and returns to live delivery. Values emitted while they are delivered,
+// including ones emitted by consumers, are queued as well and delivered
+// after them. Resume returns once the queue is empty.
+//
+// Queued emits use contexts they were given, so ones which were
+// cancelled in the meantime don't reach any consumers.
+//
+// Resuming a serial Emitter hands queued values over for delivery
+// and returns right away.
+func Resume(e *Emitter) {
+	e.resumeMu.Lock()
+	defer e.resumeMu.Unlock()
+
+	for {
+		e.queueMu.Lock()
+		queue := e.queue
+		e.queue = nil
+		if e.serial != nil {
+			for _, replay := range queue {
+				e.serial.push(e, replay)
+			}
+			e.paused.Store(false)
+			e.queueMu.Unlock()
+			return
+		}
+		if len(queue) == 0 {
+			e.paused.Store(false)
+			e.queueMu.Unlock()
+			return
+		}
+		e.queueMu.Unlock()
+
+		for _, replay := range queue {
+			replay()
+		}
+	}
+}
+
+// held reports whether emits through e may have to be queued by hold.
+func (e *Emitter) held() bool {
+	return e.serial != nil || e.paused.Load()
+}
+
+// hold queues replay to be called by Resume or, if e is serial, by its
+// dispatch goroutine, and reports whether it did, which it doesn't if
+// emits through e shouldn't be queued.
+func (e *Emitter) hold(replay func()) bool {
+	e.queueMu.Lock()
+	defer e.queueMu.Unlock()
+
+	switch {
+	case e.paused.Load():
+		e.queue = append(e.queue, replay)
+		return true
+	case e.serial != nil && !e.serial.stopped:
+		e.serial.push(e, replay)
+		return true
+	default:
+		return false
+	}
+}
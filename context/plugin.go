@@ -0,0 +1,240 @@
+package mint
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// hook is an installed plugin. It returns context and value that should
+// be passed further, optionally a function to call with outcome of the emit
+// once all consumers return, and an error to stop the emit with.
+type hook struct {
+	id   uint64
+	prio int
+	name string
+	typ  reflect.Type // of emits to hook into, or nil for all of them
+	fn   func(context.Context, any) (context.Context, any, func(error), error)
+}
+
+// Plugin is a function that can be installed with Use. It takes
+// Emitted values and returns nil or a function that will be called
+// after all consumers got the Emitted value. A plugin may also return
+// a new context, which will be passed to consumers and plugins after it.
+// Named types made of either func type are accepted as well.
+type Plugin interface {
+	~func(context.Context, any) func() |
+		~func(context.Context, any) (context.Context, func())
+}
+
+// Use allows to hook into event emitting process. Plugins are
+// called sequentially in order they were added to Emitter.
+// Plugin is a function that takes Emitted values and
+// returns nil or a function that will be called after
+// all consumers got the Emitted value. Returned functions
+// are called in reverse order via `defer` statement, so that
+// the first plugin's function is called last. Plugins which
+// return nil are skipped without affecting that order.
+// Use with nil Emitter, as well as its variants, installs nothing.
+//
+// Plugins which also return a context replace the context of the emit
+// for consumers and following plugins. Returning nil context keeps it.
+//
+// Call to unuse removes plugin before returning and returns a chan which
+// is already closed. Emits which are already in progress may still call it.
+func Use[P Plugin](e *Emitter, plugin P) (unuse func() <-chan struct{}) {
+	return use(e, nil, 0, "", hooked(plugin))
+}
+
+// UseP installs a plugin like Use, but with given priority. Plugins with
+// higher priority are called before those with lower one, regardless of
+// when they were installed, while ones with equal priority are called in
+// order they were installed in. Plugins installed with Use and its other
+// variants have priority of 0, so UseP with a positive priority puts
+// a plugin in front of them, and with a negative one after them.
+func UseP[P Plugin](e *Emitter, priority int, plugin P) (unuse func() <-chan struct{}) {
+	return use(e, nil, priority, "", hooked(plugin))
+}
+
+// UseNamed installs a plugin like Use, but with given name, which is
+// listed by Plugins, so that plugins can be told apart.
+func UseNamed[P Plugin](e *Emitter, name string, plugin P) (unuse func() <-chan struct{}) {
+	return use(e, nil, 0, name, hooked(plugin))
+}
+
+// Plugins returns names of all currently installed plugins, in order
+// they are called in. Plugins installed without a name are listed as
+// "plugin#N", where N is their position in the list, starting from 1.
+func Plugins(e *Emitter) []string {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	names := make([]string, len(e.plugins))
+	for i, p := range e.plugins {
+		names[i] = p.name
+		if p.name == "" {
+			names[i] = "plugin#" + strconv.Itoa(i+1)
+		}
+	}
+	return names
+}
+
+// hooked adapts plugin to hook.fn.
+func hooked[P Plugin](plugin P) func(context.Context, any) (context.Context, any, func(error), error) {
+	switch p := unnamed(plugin).(type) {
+	case func(context.Context, any) func():
+		return func(ctx context.Context, v any) (context.Context, any, func(error), error) {
+			return ctx, v, ignoring(p(ctx, v)), nil
+		}
+
+	case func(context.Context, any) (context.Context, func()):
+		return func(ctx context.Context, v any) (context.Context, any, func(error), error) {
+			next, after := p(ctx, v)
+			if next == nil {
+				next = ctx
+			}
+			return next, v, ignoring(after), nil
+		}
+	}
+
+	panic("unreachable")
+}
+
+// unnamed converts plugin of a named type to the func type it is
+// made of, so that both can be told apart by a type switch.
+func unnamed[P Plugin](plugin P) any {
+	v := reflect.ValueOf(plugin)
+	if v.Type().Name() == "" {
+		return plugin
+	}
+
+	for _, t := range []reflect.Type{
+		typeOf[func(context.Context, any) func()](),
+		typeOf[func(context.Context, any) (context.Context, func())](),
+	} {
+		if v.Type().ConvertibleTo(t) {
+			return v.Convert(t).Interface()
+		}
+	}
+	return plugin
+}
+
+// ignoring adapts after, which may be nil, to a function
+// that discards outcome of the emit.
+func ignoring(after func()) func(error) {
+	if after == nil {
+		return nil
+	}
+	return func(error) { after() }
+}
+
+// UseOutcome installs a plugin like Use, but functions returned by fn
+// receive outcome of the emit: errors returned by consumers registered
+// with OnE joined with ctx.Err() using errors.Join, or an error returned
+// by a guard plugin after it. They receive nil if emit went fine.
+// It is called in order with other plugins.
+func UseOutcome(e *Emitter, fn func(ctx context.Context, v any) func(err error)) (unuse func() <-chan struct{}) {
+	return use(e, nil, 0, "", func(ctx context.Context, v any) (context.Context, any, func(error), error) {
+		return ctx, v, fn(ctx, v), nil
+	})
+}
+
+// UseTransform installs a plugin like Use, which replaces emitted values with
+// ones returned by fn, so that consumers and plugins added after it
+// receive the replaced value. It is called in order with other plugins.
+//
+// Consumers are still picked by the type Emit was called with, so fn
+// must return a value of that type, otherwise Emit panics.
+func UseTransform(e *Emitter, fn func(ctx context.Context, v any) any) (unuse func() <-chan struct{}) {
+	return use(e, nil, 0, "", func(ctx context.Context, v any) (context.Context, any, func(error), error) {
+		return ctx, fn(ctx, v), nil, nil
+	})
+}
+
+// UseGuard installs a plugin like Use, which can stop values from being emitted.
+// If fn returns an error, plugins after it and consumers are not called
+// and Emit returns the error. Functions returned by plugins before the
+// guard are still called. It is called in order with other plugins.
+func UseGuard(e *Emitter, fn func(ctx context.Context, v any) error) (unuse func() <-chan struct{}) {
+	return use(e, nil, 0, "", func(ctx context.Context, v any) (context.Context, any, func(error), error) {
+		return ctx, v, nil, fn(ctx, v)
+	})
+}
+
+// UseContext installs a plugin like Use, which replaces context of emits with
+// one returned by fn, so that consumers and plugins added after it
+// receive the replaced context. It is called in order with other plugins.
+func UseContext(e *Emitter, fn func(ctx context.Context, v any) context.Context) (unuse func() <-chan struct{}) {
+	return use(e, nil, 0, "", func(ctx context.Context, v any) (context.Context, any, func(error), error) {
+		return fn(ctx, v), v, nil, nil
+	})
+}
+
+// UseContextValue installs a plugin like Use, which adds key with val to context
+// of emits, as if with context.WithValue.
+func UseContextValue(e *Emitter, key, val any) (unuse func() <-chan struct{}) {
+	return UseContext(e, func(ctx context.Context, _ any) context.Context {
+		return context.WithValue(ctx, key, val)
+	})
+}
+
+// UseFor installs a plugin like Use, which is only called for emits of T
+// and receives values as T. It is called in order with other plugins,
+// including ones installed with Use.
+func UseFor[T any](e *Emitter, fn func(context.Context, T) func()) (unuse func() <-chan struct{}) {
+	return use(e, typeOf[T](), 0, "", func(ctx context.Context, v any) (context.Context, any, func(error), error) {
+		return ctx, v, ignoring(fn(ctx, as[T](v))), nil
+	})
+}
+
+// use installs fn as a plugin for emits of typ, or all of them if typ is nil,
+// after plugins with priority higher than or equal to prio. name may be empty.
+func use(e *Emitter, typ reflect.Type, prio int, name string, fn func(context.Context, any) (context.Context, any, func(error), error)) (unuse func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.subc += 1
+	id := e.subc
+
+	// plugins are never modified in place, as emits
+	// keep using them after releasing the lock
+	i := len(e.plugins)
+	for i > 0 && e.plugins[i-1].prio < prio {
+		i -= 1
+	}
+	plugins := make([]hook, 0, len(e.plugins)+1)
+	plugins = append(plugins, e.plugins[:i]...)
+	plugins = append(plugins, hook{id: id, prio: prio, name: name, typ: typ, fn: fn})
+	e.plugins = append(plugins, e.plugins[i:]...)
+
+	done := make(chan struct{})
+	var once sync.Once
+	return func() <-chan struct{} {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+
+			// plugins are never modified in place, as emits
+			// keep using them after releasing the lock
+			plugins := make([]hook, 0, len(e.plugins))
+			for _, p := range e.plugins {
+				if p.id != id {
+					plugins = append(plugins, p)
+				}
+			}
+			e.plugins = plugins
+
+			close(done)
+		})
+		return done
+	}
+}
@@ -0,0 +1,34 @@
+package mint
+
+import "context"
+
+// EmitPooled works like Emit, but takes the value to emit from get and
+// hands it to put once all consumers returned, so that values can be
+// reused, such as with sync.Pool. Values queued by Pause or serial
+// Emitters are handed to put once they are delivered.
+//
+// Consumers must not retain the value after they return. This rules out
+// consumers which keep it for later, like ones registered with OnChan,
+// OnQueue, OnDebounce or OnThrottle, as well as sticky types and history.
+func EmitPooled[T any](e *Emitter, ctx context.Context, get func() T, put func(T)) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	v := get()
+	if e == nil {
+		put(v)
+		return ctx.Err()
+	}
+
+	if e.held() && e.hold(func() {
+		defer put(v)
+		_, _ = emitNow(e, ctx, v, nil, nil)
+	}) {
+		return nil
+	}
+
+	defer put(v)
+	_, err := emitNow(e, ctx, v, nil, nil)
+	return err
+}
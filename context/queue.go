@@ -0,0 +1,105 @@
+package mint
+
+import (
+	"context"
+	"sync"
+)
+
+// OnQueue registers a new consumer like On, but fn is called from its own
+// goroutine, which takes values from a queue of given size in order they
+// were emitted, so that Emits don't wait for fn to return. Once the queue
+// is full, policy decides what happens to further values. Sizes below 1
+// are treated as 1.
+//
+// fn receives context of the Emit which queued the value, which may be
+// cancelled by the time fn is called. Panics of fn are reported to the
+// panic handler of e, if one is set.
+//
+// Call to off unsubscribes and returns a chan which will get closed once
+// fn returns for all values which were already queued. Calling OffSync
+// from within fn blocks forever. Closing e calls off, so the goroutine
+// doesn't outlive it.
+func OnQueue[T any](e *Emitter, size int, policy Overflow, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	if size < 1 {
+		size = 1
+	}
+
+	type item struct {
+		ctx context.Context
+		v   T
+	}
+
+	q := make(chan item, size)
+	lost := func(it item) { drop(e, typeOf[T](), it.v) }
+	quit := make(chan struct{})
+
+	// closed is guarded by mu so that q is never closed mid-send
+	var mu sync.RWMutex
+	var closed bool
+
+	e.mu.Lock()
+	stop, c, err := subscribe(e, 0, "", func(ctx context.Context, v T) error {
+		mu.RLock()
+		defer mu.RUnlock()
+		if closed {
+			return nil
+		}
+		e.flight.add(1)
+		sent, dropped := offer(ctx, q, item{ctx, v}, policy, quit, lost)
+		if !sent {
+			dropped += 1
+		}
+		e.flight.add(-dropped)
+		return nil
+	})
+	e.mu.Unlock()
+
+	if err != nil {
+		return noop
+	}
+	closing := e.closingChan()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for it := range q {
+			e.mu.RLock()
+			h := e.onPanic
+			e.mu.RUnlock()
+
+			_ = call(h, c, func(ctx context.Context, v T) error {
+				fn(ctx, v)
+				return nil
+			}, it.ctx, it.v)
+			e.flight.add(-1)
+		}
+	}()
+
+	var once sync.Once
+	off = func() <-chan struct{} {
+		once.Do(func() {
+			close(quit)
+			<-stop()
+
+			mu.Lock()
+			closed = true
+			close(q)
+			mu.Unlock()
+		})
+		return done
+	}
+
+	// stop the goroutine once e is closed
+	go func() {
+		select {
+		case <-closing:
+			off()
+		case <-quit:
+		}
+	}()
+	return off
+}
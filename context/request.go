@@ -0,0 +1,40 @@
+package mint
+
+import "context"
+
+// Request emits req and waits for the first value emitted as Resp for
+// which match returns true, and returns it. The consumer waiting for
+// the response is registered before req is emitted, so responses which
+// are emitted by consumers of req are not missed, and it is always
+// unsubscribed before Request returns.
+//
+// If emitting req fails, its error is returned. If ctx is cancelled
+// before a matching response, zero value and ctx.Err() are returned.
+// Using nil context will use context.Background() instead.
+func Request[Req, Resp any](e *Emitter, ctx context.Context, req Req, match func(Req, Resp) bool) (Resp, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var zero Resp
+	ch := make(chan Resp, 1)
+	off := OnUntil(e, func(_ context.Context, resp Resp) bool {
+		if !match(req, resp) {
+			return false
+		}
+		ch <- resp
+		return true
+	})
+	defer off()
+
+	if err := Emit(e, ctx, req); err != nil {
+		return zero, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
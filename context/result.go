@@ -0,0 +1,41 @@
+package mint
+
+import "context"
+
+// EmitResult describes how far an emit got.
+type EmitResult struct {
+	// Delivered is the number of consumers which were called.
+	Delivered int
+	// Total is the number of consumers the value was going to be pushed to.
+	Total int
+	// Err is what Emit would have returned.
+	Err error
+}
+
+// EmitR works like Emit, but also reports how many consumers were called
+// out of how many there were, so that partially delivered values can be
+// told apart from ones delivered to everyone. Delivered may be less than
+// Total without Err if consumers were removed during the emit. Values
+// queued by Pause or serial Emitters report zero consumers.
+func EmitR[T any](e *Emitter, ctx context.Context, v T) EmitResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if e == nil {
+		return EmitResult{Err: ctx.Err()}
+	}
+
+	if e.held() && e.hold(func() { _, _ = emitNow(e, ctx, v, nil, nil) }) {
+		return EmitResult{}
+	}
+
+	ctx, s, err := snap(e, ctx, typeOf[T](), all, typed[T])
+	if err != nil {
+		return EmitResult{Err: err}
+	}
+
+	r := EmitResult{Total: len(s.subs)}
+	r.Delivered, r.Err = deliver(ctx, v, &s, nil, nil)
+	return r
+}
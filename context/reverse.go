@@ -0,0 +1,49 @@
+package mint
+
+import "context"
+
+// EmitReverse works like Emit, but calls consumers in reverse of the
+// order Emit calls them in, so that consumers with lower priority are
+// called first, and ones with equal priority are called in reverse
+// order of registration if the Emitter is ordered. This lets consumers
+// which were set up first be torn down last.
+func EmitReverse[T any](e *Emitter, ctx context.Context, v T) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if e == nil {
+		return ctx.Err()
+	}
+
+	if e.held() && e.hold(func() { _ = emitReverse(e, ctx, v) }) {
+		return nil
+	}
+	return emitReverse(e, ctx, v)
+}
+
+// emitReverse works like EmitReverse with non-nil e and ctx, but ignores Pause.
+func emitReverse[T any](e *Emitter, ctx context.Context, v T) error {
+	ctx, s, err := snap(e, ctx, typeOf[T](), reversed, typed[T])
+	if err != nil {
+		return err
+	}
+
+	_, err = deliver(ctx, v, &s, nil, nil)
+	return err
+}
+
+// reversed lists consumers in b like all, but in reverse order.
+// Caller must hold e.mu.
+func reversed(e *Emitter, b *bucket) []*sub {
+	subs := all(e, b)
+	if len(subs) == 0 {
+		return nil
+	}
+
+	r := make([]*sub, len(subs))
+	for i, s := range subs {
+		r[len(subs)-1-i] = s
+	}
+	return r
+}
@@ -0,0 +1,48 @@
+package mint
+
+import (
+	"context"
+	"fmt"
+)
+
+// PanicError is a panic of a consumer, recovered by EmitSafe.
+type PanicError struct {
+	// Consumer is the consumer which panicked.
+	Consumer Consumer
+	// Value is the value recovered from the panic.
+	Value any
+}
+
+func (err *PanicError) Error() string {
+	c := err.Consumer
+	if c.Name != "" {
+		return fmt.Sprintf("mint: consumer %d (%s) of %v panicked: %v", c.ID, c.Name, c.Type, err.Value)
+	}
+	return fmt.Sprintf("mint: consumer %d of %v panicked: %v", c.ID, c.Type, err.Value)
+}
+
+// EmitSafe works like Emit, but recovers panics of consumers and returns
+// them as *PanicError, in order consumers were called in, so that one
+// consumer which panics doesn't stop others from being called. Unlike
+// EmitErr, errors returned by consumers are discarded. The panic handler
+// is not called for recovered panics. err is one of the errors
+// returned by Emit.
+func EmitSafe[T any](e *Emitter, ctx context.Context, v T) (panics []error, err error) {
+	_, err = emit(e, ctx, v, safe[T], func(err error) bool {
+		if _, ok := err.(*PanicError); ok {
+			panics = append(panics, err)
+		}
+		return false
+	})
+	return panics, err
+}
+
+// safe is an invoker which returns panics of consumers as *PanicError.
+func safe[T any](_ PanicHandler, c Consumer, fn func(context.Context, T) error, ctx context.Context, v T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Consumer: c, Value: r}
+		}
+	}()
+	return fn(ctx, v)
+}
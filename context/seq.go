@@ -0,0 +1,49 @@
+package mint
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// seqKey is the context key of the number an emit got from c.
+type seqKey struct{ c *atomic.Uint64 }
+
+// OnSeq registers a new consumer like On, which also receives sequence
+// number of the emit. Once e has a consumer registered with OnSeq, every
+// emit through e, regardless of its type, gets a number one greater than
+// the emit before it, starting from 1. Concurrent emits get distinct
+// numbers, in order they started in.
+func OnSeq[T any](e *Emitter, fn func(ctx context.Context, seq uint64, v T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.sequenced = true
+	key := seqKey{&e.seq}
+	return on(e, 0, func(ctx context.Context, v T) error {
+		seq, _ := ctx.Value(key).(uint64)
+		fn(ctx, seq, v)
+		return nil
+	})
+}
+
+// sequence returns the emit counter of e if its consumers need it,
+// or nil otherwise. Caller must hold e.mu.
+func (e *Emitter) sequence() *atomic.Uint64 {
+	if !e.sequenced {
+		return nil
+	}
+	return &e.seq
+}
+
+// number returns context carrying the next number of c,
+// or ctx itself if c is nil.
+func number(ctx context.Context, c *atomic.Uint64) context.Context {
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, seqKey{c}, c.Add(1))
+}
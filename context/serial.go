@@ -0,0 +1,81 @@
+package mint
+
+// serial is the dispatch queue of a serial Emitter.
+// Its fields are guarded by queueMu of the Emitter.
+type serial struct {
+	queue   []func()
+	wake    chan struct{}
+	quit    chan struct{}
+	stopped bool
+}
+
+// NewSerialEmitter creates an Emitter which delivers all values by a single
+// goroutine, strictly in order they were emitted in, so that consumers never
+// run concurrently with each other and see values of concurrent Emits one
+// after another. This trades throughput for ordering.
+//
+// Emits return once their value is queued, before it is delivered, so they
+// can't report errors of consumers and EmitN reports 0 consumers. Drain
+// waits for queued values to be delivered. Consumers are free to emit, in
+// which case the value is queued after ones which are already queued.
+// EmitOne and EmitGroup deliver values right away.
+//
+// The goroutine is stopped by Close, dropping values which are still queued.
+// Their emits see ErrClosed, so chans returned by EmitAsync and EmitPool
+// for them get closed, and EmitPooled hands them to put.
+func NewSerialEmitter() *Emitter {
+	e := &Emitter{serial: &serial{
+		wake: make(chan struct{}, 1),
+		quit: make(chan struct{}),
+	}}
+	go e.serial.run(e)
+	return e
+}
+
+// push queues replay to be called by the dispatch goroutine.
+// Caller must hold e.queueMu.
+func (s *serial) push(e *Emitter, replay func()) {
+	e.flight.add(1)
+	s.queue = append(s.queue, replay)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// stop makes the dispatch goroutine exit and returns queued values,
+// which are still counted as in flight, so that the caller can replay
+// them once e is closed and they only clean up after themselves.
+// Caller must hold e.queueMu.
+func (s *serial) stop(e *Emitter) (dropped []func()) {
+	if s.stopped {
+		return nil
+	}
+
+	s.stopped = true
+	close(s.quit)
+	dropped = s.queue
+	s.queue = nil
+	return dropped
+}
+
+// run calls queued functions until s is stopped.
+func (s *serial) run(e *Emitter) {
+	for {
+		select {
+		case <-s.wake:
+		case <-s.quit:
+			return
+		}
+
+		e.queueMu.Lock()
+		queue := s.queue
+		s.queue = nil
+		e.queueMu.Unlock()
+
+		for _, replay := range queue {
+			replay()
+			e.flight.add(-1)
+		}
+	}
+}
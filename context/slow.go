@@ -0,0 +1,32 @@
+package mint
+
+import "time"
+
+// SetSlowConsumerHandler makes Emit measure how long each consumer takes
+// and report ones which take longer than d to h, after they return. It
+// doesn't affect delivery, but h is called synchronously by Emit, so it
+// should return quickly. Consumers called by EmitAsync and EmitPool are
+// not measured.
+//
+// Using nil h stops reporting, which is the default.
+func SetSlowConsumerHandler(e *Emitter, d time.Duration, h func(c Consumer, elapsed time.Duration)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.slowAfter, e.onSlow = d, h
+}
+
+// timing reports whether consumers of s have to be timed.
+func (s *snapshot[T]) timing() bool {
+	return s.onSlow != nil || s.stats
+}
+
+// timed counts a call of i-th consumer of s, if stats are enabled,
+// and reports it if it took longer than allowed.
+func (s *snapshot[T]) timed(i int, elapsed time.Duration) {
+	if s.stats {
+		s.count(i, elapsed)
+	}
+	if s.onSlow != nil && elapsed > s.slowAfter {
+		s.onSlow(s.consumer(i), elapsed)
+	}
+}
@@ -0,0 +1,56 @@
+package mint
+
+import (
+	"sort"
+	"time"
+)
+
+// SubStat is how many times a consumer was called and how long it took.
+type SubStat struct {
+	Consumer
+	// Calls is the number of times consumer was called.
+	Calls uint64
+	// Total is the time spent in all calls of the consumer.
+	Total time.Duration
+}
+
+// SetStatsEnabled makes emits through e count calls of each consumer
+// and time spent in them, which Stats reports. Emits which are already
+// in progress may not be counted. Stats are disabled by default, as
+// timing consumers makes emits slower.
+func SetStatsEnabled(e *Emitter, on bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stats = on
+}
+
+// Stats returns stats of all currently registered consumers,
+// in order they were registered in. Calls made while stats
+// were disabled are not counted.
+func Stats(e *Emitter) []SubStat {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var stats []SubStat
+	for t, b := range e.subs {
+		for _, s := range b.subs {
+			stats = append(stats, SubStat{
+				Consumer: Consumer{ID: s.id, Type: t, Name: s.name},
+				Calls:    s.calls.Load(),
+				Total:    time.Duration(s.spent.Load()),
+			})
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ID < stats[j].ID })
+	return stats
+}
+
+// count counts a call of i-th consumer of s which took elapsed.
+func (s *snapshot[T]) count(i int, elapsed time.Duration) {
+	s.from[i].calls.Add(1)
+	s.from[i].spent.Add(int64(elapsed))
+}
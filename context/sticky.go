@@ -0,0 +1,327 @@
+package mint
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// retained holds values last emitted as a type, up to its capacity.
+type retained struct {
+	mu   sync.Mutex
+	vs   []entry // ring buffer
+	next int     // where to put next value
+	full bool
+	// emits which took a snapshot with r get a ticket, which stays open
+	// until they add their value, so that OnWithReplay can wait for them
+	tickets uint64
+	open    map[uint64]struct{}
+	closed  sync.Cond
+}
+
+// entry is a retained value, along with ticket of the emit which added it.
+type entry struct {
+	v      any
+	ticket uint64
+}
+
+func newRetained(n int) *retained {
+	r := &retained{vs: make([]entry, n)}
+	r.closed.L = &r.mu
+	return r
+}
+
+// take opens a new ticket. Caller must hold e.mu of the Emitter.
+func (r *retained) take() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tickets += 1
+	if r.open == nil {
+		r.open = make(map[uint64]struct{})
+	}
+	r.open[r.tickets] = struct{}{}
+	return r.tickets
+}
+
+// issued returns the latest ticket. Caller must hold e.mu of the Emitter.
+func (r *retained) issued() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tickets
+}
+
+// done closes ticket, if it is still open.
+func (r *retained) done(ticket uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.open[ticket]; ok {
+		delete(r.open, ticket)
+		r.closed.Broadcast()
+	}
+}
+
+// add puts v into the buffer, evicting the oldest value if it is full.
+func (r *retained) add(v any, ticket uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.vs[r.next] = entry{v, ticket}
+	r.next = (r.next + 1) % len(r.vs)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// values returns buffered values, oldest first.
+func (r *retained) values() []any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	es := r.ordered()
+	vs := make([]any, len(es))
+	for i, e := range es {
+		vs[i] = e.v
+	}
+	return vs
+}
+
+// until waits for tickets up to mark to close and returns up to n latest
+// buffered values which were added with them, oldest first.
+func (r *retained) until(mark uint64, n int) []any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.opened(mark) {
+		r.closed.Wait()
+	}
+
+	var vs []any
+	for _, e := range r.ordered() {
+		if e.ticket <= mark {
+			vs = append(vs, e.v)
+		}
+	}
+	if len(vs) > n {
+		vs = vs[len(vs)-n:]
+	}
+	return vs
+}
+
+// opened reports whether any ticket up to mark is still open.
+// Caller must hold r.mu.
+func (r *retained) opened(mark uint64) bool {
+	for t := range r.open {
+		if t <= mark {
+			return true
+		}
+	}
+	return false
+}
+
+// ordered returns buffered values, oldest first. Caller must hold r.mu.
+func (r *retained) ordered() []entry {
+	if !r.full {
+		return append([]entry(nil), r.vs[:r.next]...)
+	}
+	return append(append([]entry(nil), r.vs[r.next:]...), r.vs[:r.next]...)
+}
+
+// last returns the latest buffered value.
+func (r *retained) last() (any, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full && r.next == 0 {
+		return nil, false
+	}
+	return r.vs[(r.next+len(r.vs)-1)%len(r.vs)].v, true
+}
+
+// resize changes capacity of the buffer to n, keeping latest values.
+func (r *retained) resize(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vs := r.ordered()
+	if len(vs) > n {
+		vs = vs[len(vs)-n:]
+	}
+	r.vs = make([]entry, n)
+	copy(r.vs, vs)
+	r.next = len(vs) % n
+	r.full = len(vs) == n
+}
+
+func (r *retained) drop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.vs = make([]entry, len(r.vs))
+	r.next, r.full = 0, false
+}
+
+// Sticky makes Emitter retain the last value emitted as T, so that
+// consumers registered with OnSticky receive it right away.
+//
+// Each sticky type keeps its last value for as long as the Emitter lives,
+// unless it is dropped with Clear or ClearType. Types with history
+// configured by WithHistory are already sticky.
+func Sticky[T any](e *Emitter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.retained[typeOf[T]()]; ok {
+		return
+	}
+	if e.retained == nil {
+		e.retained = make(map[reflect.Type]*retained)
+	}
+	e.retained[typeOf[T]()] = newRetained(1)
+}
+
+// WithHistory makes Emitter retain up to n last values emitted as T,
+// evicting the oldest ones once there are more. Retained values can
+// be iterated with Replay. Using n <= 0 stops retaining values of T.
+//
+// Changing n of a type which already has history keeps the latest values.
+// Types with history are sticky.
+func WithHistory[T any](e *Emitter, n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if n <= 0 {
+		delete(e.retained, typeOf[T]())
+		return
+	}
+
+	if r, ok := e.retained[typeOf[T]()]; ok {
+		r.resize(n)
+		return
+	}
+	if e.retained == nil {
+		e.retained = make(map[reflect.Type]*retained)
+	}
+	e.retained[typeOf[T]()] = newRetained(n)
+}
+
+// Replay calls fn with values retained for T, oldest first.
+func Replay[T any](e *Emitter, fn func(T)) {
+	e.mu.RLock()
+	r := e.retained[typeOf[T]()]
+	e.mu.RUnlock()
+
+	if r == nil {
+		return
+	}
+	for _, v := range r.values() {
+		fn(as[T](v))
+	}
+}
+
+// OnSticky registers a new consumer like On. If T is sticky and a value
+// was emitted as T before, fn is called with it before OnSticky returns.
+// Nothing is replayed if no value was emitted yet.
+//
+// A value emitted concurrently with the call may be delivered
+// before the replayed one.
+func OnSticky[T any](e *Emitter, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	off = on(e, 0, func(ctx context.Context, v T) error {
+		fn(ctx, v)
+		return nil
+	})
+	r := e.retained[typeOf[T]()]
+	e.mu.Unlock()
+
+	if r == nil {
+		return off
+	}
+	if v, ok := r.last(); ok {
+		fn(context.Background(), as[T](v))
+	}
+	return off
+}
+
+// OnWithReplay registers a new consumer like On, but first calls fn with
+// up to k latest values retained for T, oldest first, before returning.
+// T must be sticky or have history configured by WithHistory, otherwise
+// nothing is replayed.
+//
+// Replayed values and live ones never overlap and leave no gap between
+// them: values of emits which started before OnWithReplay are the ones
+// replayed, waiting for them to be retained if they are still passing
+// through plugins, while values of emits which start after it are
+// delivered live. Live values which arrive during replay are held until it is over
+// and then passed to fn from OnWithReplay, so fn receives no live value
+// before all replayed ones. Replayed values receive context.Background().
+//
+// As it waits for emits in progress, calling OnWithReplay of T from
+// plugins, or from consumers of values emitted as T with EmitAll,
+// blocks forever.
+func OnWithReplay[T any](e *Emitter, k int, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	type item struct {
+		ctx context.Context
+		v   T
+	}
+
+	// live values are held in pending while replaying
+	var mu sync.Mutex
+	replaying := true
+	var pending []item
+
+	e.mu.Lock()
+	off, _, err := subscribe(e, 0, "", func(ctx context.Context, v T) error {
+		mu.Lock()
+		if replaying {
+			pending = append(pending, item{ctx, v})
+			mu.Unlock()
+			return nil
+		}
+		mu.Unlock()
+
+		fn(ctx, v)
+		return nil
+	})
+	r := e.retained[typeOf[T]()]
+	var mark uint64
+	if r != nil {
+		mark = r.issued()
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		return off
+	}
+
+	if r != nil && k > 0 {
+		for _, v := range r.until(mark, k) {
+			fn(context.Background(), as[T](v))
+		}
+	}
+
+	for {
+		mu.Lock()
+		held := pending
+		pending = nil
+		if len(held) == 0 {
+			replaying = false
+			mu.Unlock()
+			return off
+		}
+		mu.Unlock()
+
+		for _, it := range held {
+			fn(it.ctx, it.v)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package mint
+
+import (
+	"context"
+	"errors"
+)
+
+// errStop is returned by consumers registered with OnStop which called stop.
+var errStop = errors.New("mint: emit stopped by consumer")
+
+// OnStop registers a new consumer like On, but fn also receives stop,
+// calling which makes the Emit skip remaining consumers and return once
+// fn does. Emit doesn't report it as an error. EmitAsync, EmitPool and
+// EmitGroup, which call consumers concurrently, ignore stop.
+func OnStop[T any](e *Emitter, fn func(ctx context.Context, v T, stop func())) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return on(e, 0, func(ctx context.Context, v T) error {
+		stopped := false
+		fn(ctx, v, func() { stopped = true })
+		if stopped {
+			return errStop
+		}
+		return nil
+	})
+}
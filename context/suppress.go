@@ -0,0 +1,41 @@
+package mint
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Suppress makes following emits of T through e discard their values,
+// without calling plugins or consumers, until resume is called. Unlike
+// Pause, it only affects emits of T, and values are not delivered later.
+// Emits of T which are already in progress are not affected.
+//
+// Suppressing T more than once keeps it suppressed until all of returned
+// resumes are called. Calling resume more than once does nothing.
+func Suppress[T any](e *Emitter) (resume func()) {
+	if e == nil {
+		return func() {}
+	}
+
+	t := typeOf[T]()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.suppressed == nil {
+		e.suppressed = make(map[reflect.Type]int)
+	}
+	e.suppressed[t] += 1
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+
+			if e.suppressed[t] -= 1; e.suppressed[t] <= 0 {
+				delete(e.suppressed, t)
+			}
+		})
+	}
+}
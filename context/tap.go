@@ -0,0 +1,68 @@
+package mint
+
+import (
+	"reflect"
+	"sync"
+)
+
+// tap is an observer installed with Tap.
+type tap struct {
+	id uint64
+	fn func(typeName string, v any)
+}
+
+// Tap installs fn to observe all values emitted through e along with
+// name of the type they were emitted as. fn is called after plugins,
+// with the value consumers receive, before any of them are called.
+// Values stopped by a guard plugin are not observed.
+//
+// Unlike plugins, fn can't affect the emit. It is called synchronously,
+// so it should return quickly.
+//
+// Call to untap works like unuse returned by Use.
+func Tap(e *Emitter, fn func(typeName string, v any)) (untap func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.subc += 1
+	id := e.subc
+	e.taps = append(e.taps, tap{id: id, fn: fn})
+
+	done := make(chan struct{})
+	var once sync.Once
+	return func() <-chan struct{} {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+
+			// taps are never modified in place, as emits
+			// keep using them after releasing the lock
+			taps := make([]tap, 0, len(e.taps))
+			for _, t := range e.taps {
+				if t.id != id {
+					taps = append(taps, t)
+				}
+			}
+			e.taps = taps
+
+			close(done)
+		})
+		return done
+	}
+}
+
+// tapAll passes v, emitted as t, to all of taps.
+func tapAll[T any](taps []tap, t reflect.Type, v T) {
+	if len(taps) == 0 {
+		return
+	}
+
+	name := t.String()
+	for _, tap := range taps {
+		tap.fn(name, v)
+	}
+}
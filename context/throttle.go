@@ -0,0 +1,129 @@
+package mint
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OnThrottle registers a new consumer like On, but fn is called at most
+// once per d. A value emitted while no interval is running is passed to fn
+// right away and starts one. Values emitted during the interval are dropped,
+// except for the last of them, which is passed to fn once the interval ends,
+// starting another one. Such trailing values are passed from their own
+// goroutine, along with context of their Emit, which may be cancelled by
+// then, and panics of fn are reported to the panic handler of e, if one
+// is set. Drain waits for trailing values to be passed to fn.
+//
+// Call to off unsubscribes, drops the trailing value, if any, and returns
+// a chan which will get closed once fn returns for it, if it is running.
+// Closing e drops the trailing value as well.
+func OnThrottle[T any](e *Emitter, d time.Duration, fn func(context.Context, T)) (off func() <-chan struct{}) {
+	if e == nil {
+		return noop
+	}
+
+	var mu sync.Mutex
+	var timer *time.Timer
+	var running sync.WaitGroup
+	var stopped, open, pending bool
+	var last struct {
+		ctx context.Context
+		v   T
+	}
+
+	var c Consumer
+	closing := e.closingChan()
+	fire := func() {
+		mu.Lock()
+		if stopped || !pending {
+			open = false
+			mu.Unlock()
+			return
+		}
+		pending = false
+		select {
+		case <-closing:
+			// e was closed since the value was emitted
+			open = false
+			last.ctx, last.v = nil, *new(T)
+			mu.Unlock()
+			e.flight.add(-1)
+			return
+		default:
+		}
+		ctx, v := last.ctx, last.v
+		last.ctx, last.v = nil, *new(T)
+		timer.Reset(d)
+		running.Add(1)
+		mu.Unlock()
+
+		defer running.Done()
+		defer e.flight.add(-1)
+
+		e.mu.RLock()
+		h := e.onPanic
+		e.mu.RUnlock()
+
+		_ = call(h, c, func(ctx context.Context, v T) error {
+			fn(ctx, v)
+			return nil
+		}, ctx, v)
+	}
+
+	e.mu.Lock()
+	stop, c, _ := subscribe(e, 0, "", func(ctx context.Context, v T) error {
+		mu.Lock()
+		if stopped {
+			mu.Unlock()
+			return nil
+		}
+
+		if open {
+			last.ctx, last.v = ctx, v
+			if !pending {
+				pending = true
+				e.flight.add(1)
+			}
+			mu.Unlock()
+			return nil
+		}
+
+		open = true
+		if timer == nil {
+			timer = time.AfterFunc(d, fire)
+		} else {
+			timer.Reset(d)
+		}
+		mu.Unlock()
+
+		fn(ctx, v)
+		return nil
+	})
+	e.mu.Unlock()
+
+	done := make(chan struct{})
+	var once sync.Once
+	return func() <-chan struct{} {
+		once.Do(func() {
+			<-stop()
+
+			mu.Lock()
+			stopped = true
+			if timer != nil {
+				timer.Stop()
+			}
+			if pending {
+				pending = false
+				e.flight.add(-1)
+			}
+			mu.Unlock()
+
+			go func() {
+				running.Wait()
+				close(done)
+			}()
+		})
+		return done
+	}
+}
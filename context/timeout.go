@@ -0,0 +1,111 @@
+package mint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is reported for consumers that took too long to return.
+var ErrTimeout = errors.New("mint: consumer timed out")
+
+// EmitTimeout works like EmitErr, but gives each consumer d to return.
+// A consumer that takes longer is left running in its own goroutine and
+// emitting continues with the next one. Returned error joins an error
+// wrapping ErrTimeout for every such consumer with errors of the others.
+//
+// Panics of a consumer which timed out can only be recovered by
+// a panic handler and are discarded otherwise.
+func EmitTimeout[T any](e *Emitter, ctx context.Context, v T, d time.Duration) error {
+	var errs []error
+	_, err := emit(e, ctx, v, timeout[T](d), func(err error) bool {
+		errs = append(errs, err)
+		return false
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// timeout returns an invoker which waits up to d for consumers to return.
+func timeout[T any](d time.Duration) invoker[T] {
+	return func(h PanicHandler, c Consumer, fn func(context.Context, T) error, ctx context.Context, v T) error {
+		res := detach(fn, ctx, v)
+
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case r := <-res:
+			return settle(h, c, r, v)
+		case <-t.C:
+			abandon(h, c, res, v)
+			return fmt.Errorf("%w: consumer of %v took longer than %v", ErrTimeout, typeOf[T](), d)
+		}
+	}
+}
+
+// deadline is an invoker which waits for consumers to return until
+// ctx is done. Emit returns ctx.Err() for consumers left running.
+func deadline[T any](h PanicHandler, c Consumer, fn func(context.Context, T) error, ctx context.Context, v T) error {
+	res := detach(fn, ctx, v)
+
+	select {
+	case r := <-res:
+		return settle(h, c, r, v)
+	case <-ctx.Done():
+		abandon(h, c, res, v)
+		return nil
+	}
+}
+
+// result is an outcome of a consumer called by detach.
+type result struct {
+	err      error
+	panicked bool
+	r        any
+}
+
+// detach calls fn with v in its own goroutine and returns
+// a chan which receives its result.
+func detach[T any](fn func(context.Context, T) error, ctx context.Context, v T) <-chan result {
+	res := make(chan result, 1)
+	go func() {
+		panicked := true
+		defer func() {
+			if panicked {
+				res <- result{panicked: true, r: recover()}
+			}
+		}()
+		err := fn(ctx, v)
+		panicked = false
+		res <- result{err: err}
+	}()
+	return res
+}
+
+// settle returns error of r, reporting its panic to h,
+// or panicking again if h is nil.
+func settle[T any](h PanicHandler, c Consumer, r result, v T) error {
+	if !r.panicked {
+		return r.err
+	}
+	if h == nil {
+		panic(r.r)
+	}
+	h(c, r.r, v)
+	return nil
+}
+
+// abandon stops waiting for res, still reporting
+// its panic to h if it is not nil.
+func abandon[T any](h PanicHandler, c Consumer, res <-chan result, v T) {
+	if h == nil {
+		return
+	}
+	go func() {
+		if r := <-res; r.panicked {
+			defer func() { _ = recover() }()
+			h(c, r.r, v)
+		}
+	}()
+}
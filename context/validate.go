@@ -0,0 +1,22 @@
+package mint
+
+import "context"
+
+// Validator is implemented by values which can tell whether they are
+// well-formed, to be checked by EmitValidated before they are emitted.
+type Validator interface {
+	Validate() error
+}
+
+// EmitValidated works like Emit, but if v implements Validator, calls its
+// Validate method first. If it returns an error, no plugins or consumers
+// are called and EmitValidated returns that error. Values which don't
+// implement Validator are emitted as is.
+func EmitValidated[T any](e *Emitter, ctx context.Context, v T) error {
+	if val, ok := any(v).(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return err
+		}
+	}
+	return Emit(e, ctx, v)
+}
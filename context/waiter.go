@@ -0,0 +1,79 @@
+package mint
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Waiter waits for the first of values of several types to be emitted,
+// like a select statement over events. Cases are added with AddCase.
+// The zero Waiter is not usable, use NewWaiter instead.
+type Waiter struct {
+	e     *Emitter
+	cases []func(e *Emitter, fire func(v any)) (off func() <-chan struct{})
+}
+
+// NewWaiter returns a Waiter for values emitted with e.
+func NewWaiter(e *Emitter) *Waiter {
+	return &Waiter{e: e}
+}
+
+// AddCase adds a case for values emitted as T to w and returns its index,
+// which Wait returns once the case fires. Cases must not be added
+// during a call to Wait.
+func AddCase[T any](w *Waiter) (index int) {
+	w.cases = append(w.cases, func(e *Emitter, fire func(v any)) func() <-chan struct{} {
+		return on(e, 0, func(_ context.Context, v T) error {
+			fire(v)
+			return nil
+		})
+	})
+	return len(w.cases) - 1
+}
+
+// Wait blocks until a value is emitted as the type of one of cases of w
+// and returns index of that case along with the value. Consumers of all
+// cases are registered at once when Wait is called, and removed before
+// it returns, so values emitted before or after that are not seen.
+// If ctx is cancelled first, -1, nil and ctx.Err() are returned.
+// Using nil context will use context.Background() instead.
+func (w *Waiter) Wait(ctx context.Context) (index int, v any, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	type result struct {
+		index int
+		v     any
+	}
+
+	ch := make(chan result, 1)
+	var fired atomic.Bool
+	offs := make([]func() <-chan struct{}, 0, len(w.cases))
+
+	if w.e != nil {
+		w.e.mu.Lock()
+		for i, c := range w.cases {
+			i := i
+			offs = append(offs, c(w.e, func(v any) {
+				if fired.CompareAndSwap(false, true) {
+					ch <- result{i, v}
+				}
+			}))
+		}
+		w.e.mu.Unlock()
+	}
+
+	defer func() {
+		for _, off := range offs {
+			off()
+		}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.index, r.v, nil
+	case <-ctx.Done():
+		return -1, nil, ctx.Err()
+	}
+}
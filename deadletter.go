@@ -0,0 +1,25 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// SetDeadLetter makes Emitter pass values which were dropped instead of
+// being delivered to fn, along with the type they were emitted as, so
+// that they can be inspected. Values are dropped by buffered consumers
+// which are full, by Suppress and by SetMaxFanout. Using nil fn stops
+// passing them, which is the default.
+//
+// fn is called from a goroutine of its own, in order values were dropped,
+// and never holds up Emits: dropped values wait for it in a buffer of
+// given size, and once the buffer is full, further ones are discarded
+// and counted by DeadLetterLost. Sizes below 1 are treated as 1.
+// Setting another fn, as well as Close, stops the previous one once
+// values which are already buffered are passed to it.
+func SetDeadLetter(e *Emitter, size int, fn func(typeName string, v any)) {
+	cm.SetDeadLetter(e, size, fn)
+}
+
+// DeadLetterLost returns the number of dropped values which were
+// discarded by the current dead letter handler, as its buffer was full.
+func DeadLetterLost(e *Emitter) uint64 {
+	return cm.DeadLetterLost(e)
+}
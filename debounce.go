@@ -0,0 +1,20 @@
+package mint
+
+import (
+	"context"
+	"time"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnDebounce registers a new consumer like On, but fn is only called once
+// emits of T stop for d, with the last value emitted before that. fn is
+// called from its own goroutine. Drain waits for pending values to be
+// passed to fn.
+//
+// Call to off unsubscribes, drops the pending value, if any, and returns
+// a chan which will get closed once fn returns, if it is running.
+// Closing e drops the pending value as well.
+func OnDebounce[T any](e *Emitter, d time.Duration, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnDebounce(e, d, func(_ context.Context, v T) { fn(v) })
+}
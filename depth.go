@@ -0,0 +1,15 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// SetMaxDepth limits how deep emits made with mint/context may be nested
+// in consumers of other emits, see SetMaxDepth of mint/context.
+//
+// It is only available with the contextful api. Emits of this package
+// always start with context.Background(), so they can't tell nested emits
+// from concurrent ones, and cascades made through this package are never
+// limited. Consumers taking part in a cascade have to be registered and
+// emit with mint/context, passing on the context they receive.
+func SetMaxDepth(e *Emitter, n int) {
+	cm.SetMaxDepth(e, n)
+}
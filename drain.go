@@ -0,0 +1,18 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// Drain blocks until all deliveries which outlive their Emits are done,
+// which are ones started by EmitAsync, EmitPool and EmitGroup, and values queued
+// for consumers registered with OnQueue.
+//
+// Drain doesn't stop new emits, which it waits for as well if they start
+// before it returns, so it should be paired with Close for shutdown.
+// Emits queued by Pause are not waited for until Resume.
+func Drain(e *Emitter) {
+	_ = cm.Drain(e, context.Background())
+}
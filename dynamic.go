@@ -0,0 +1,38 @@
+package mint
+
+import (
+	"context"
+	"reflect"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// ErrTypeMismatch is returned by EmitDynamic when
+// the value can't be emitted as the given type.
+var ErrTypeMismatch = cm.ErrTypeMismatch
+
+// EmitDynamic works like Emit, but pushes v to consumers of type t,
+// which is only known at runtime, so that EmitDynamic(e,
+// reflect.TypeOf(v), v) reaches consumers registered with On[T] if v is T.
+//
+// v must be assignable to t, otherwise nothing is called and an error
+// wrapping ErrTypeMismatch is returned.
+func EmitDynamic(e *Emitter, t reflect.Type, v any) error {
+	return cm.EmitDynamic(e, context.Background(), t, v)
+}
+
+// RawOn registers a new consumer like On, but of type t, which is only
+// known at runtime, for callers which can't use type parameters. t must
+// not be nil, otherwise RawOn panics.
+//
+// RawOn shares consumers with On, so that values emitted as T, whether
+// with Emit[T] or RawEmit with t of T, reach consumers registered with
+// On[T] and RawOn of t alike. fn receives them as any, holding T.
+func RawOn(e *Emitter, t reflect.Type, fn func(v any)) (off func() <-chan struct{}) {
+	return cm.RawOn(e, t, func(_ context.Context, v any) { fn(v) })
+}
+
+// RawEmit is EmitDynamic, named to pair with RawOn.
+func RawEmit(e *Emitter, t reflect.Type, v any) error {
+	return cm.RawEmit(e, context.Background(), t, v)
+}
@@ -0,0 +1,23 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// ErrGroup runs functions in goroutines and collects their errors.
+// It is implemented by *errgroup.Group of golang.org/x/sync/errgroup.
+type ErrGroup = cm.ErrGroup
+
+// EmitGroup works like EmitAsync, but runs each consumer with g, which
+// lets the caller limit concurrency and collect errors returned by
+// consumers registered with OnE. All of them are handed to g before
+// EmitGroup returns, so it is safe to wait for g right after.
+//
+// If the emit doesn't happen, such as when e is closed or a guard plugin
+// stops it, the reason is handed to g as well. EmitGroup is not affected
+// by Pause, as consumers have to be handed to g right away.
+func EmitGroup[T any](e *Emitter, g ErrGroup, v T) {
+	cm.EmitGroup(e, context.Background(), g, v)
+}
@@ -0,0 +1,27 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnError registers a new consumer like On, which receives all non-nil values
+// implementing error that are emitted through e, whatever type they are
+// emitted as, so that failures can be observed in a single place.
+// Consumers of their own types still receive them as usual, and plugins
+// are not called again for the consumer. It is called after them, or once
+// all of them return for EmitAsync and its variants. It is not listed
+// by Consumers, Types and similar, as it isn't a consumer of a type.
+//
+// Values are only checked for implementing error while e has consumers
+// registered with OnError, so emits of other types don't pay for it.
+func OnError(e *Emitter, fn func(error)) (off func() <-chan struct{}) {
+	return cm.OnError(e, func(_ context.Context, err error) { fn(err) })
+}
+
+// EmitError emits err as error, so that it reaches consumers of error
+// as well as ones registered with OnError. Emitting nil does nothing.
+func EmitError(e *Emitter, err error) {
+	fanout(cm.EmitError(e, context.Background(), err))
+}
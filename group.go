@@ -0,0 +1,26 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// Group collects consumers of an Emitter, so that all of them can be
+// unsubscribed at once with Close. The zero Group is not usable,
+// use NewGroup instead.
+type Group = cm.Group
+
+// NewGroup returns an empty Group of consumers of e.
+func NewGroup(e *Emitter) *Group {
+	return cm.NewGroup(e)
+}
+
+// OnGroup registers a new consumer like On and adds it to g.
+// Consumers added to a closed Group are unsubscribed right away.
+//
+// Call to off removes consumer before Close does, and returns
+// a chan which is already closed.
+func OnGroup[T any](g *Group, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnGroup(g, func(_ context.Context, v T) { fn(v) })
+}
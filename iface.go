@@ -0,0 +1,26 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnIface registers a new consumer like On, but I must be an interface
+// type, otherwise OnIface panics. Along with values emitted as I, the
+// consumer receives values pushed by EmitIface which implement I.
+func OnIface[I any](e *Emitter, fn func(I)) (off func() <-chan struct{}) {
+	return cm.OnIface(e, func(_ context.Context, v I) { fn(v) })
+}
+
+// EmitIface works like Emit, but along with consumers of T, v is pushed
+// to consumers of all interface types which v implements, such as those
+// registered with OnIface. Plugins are called once, and consumers of
+// all types are called in a single pass, taking priorities and order
+// of registration into account as Emit does.
+//
+// Unlike Emit, EmitIface relies on reflection, checking v against every
+// interface type with consumers, so it is considerably slower.
+func EmitIface[T any](e *Emitter, v T) {
+	fanout(cm.EmitIface(e, context.Background(), v))
+}
@@ -0,0 +1,70 @@
+package mint
+
+import (
+	"context"
+	"reflect"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// Consumer describes a registered consumer for diagnostics.
+type Consumer = cm.Consumer
+
+// Handle refers to a registered consumer, for building
+// subscription managers on top of an Emitter.
+type Handle = cm.Handle
+
+// OnH registers a new consumer like On, but returns its Handle.
+func OnH[T any](e *Emitter, fn func(T)) Handle {
+	return cm.OnH(e, func(_ context.Context, v T) { fn(v) })
+}
+
+// OnNamed registers a new consumer like On, but with given name,
+// which is reported to panic and slow consumer handlers, and listed
+// by Consumers, so that consumers can be told apart.
+func OnNamed[T any](e *Emitter, name string, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnNamed(e, name, func(_ context.Context, v T) { fn(v) })
+}
+
+// Consumers returns all currently registered consumers,
+// in order they were registered in.
+func Consumers(e *Emitter) []Consumer {
+	return cm.Consumers(e)
+}
+
+// Types returns all types which currently have consumers,
+// along with the number of consumers of each of them.
+// Consumers registered with OnAll are not counted.
+func Types(e *Emitter) map[reflect.Type]int {
+	return cm.Types(e)
+}
+
+// TypeNames returns names of all types which currently have consumers,
+// sorted. Names are taken once the first consumer of a type registers,
+// so listing them doesn't need reflection.
+func TypeNames(e *Emitter) []string {
+	return cm.TypeNames(e)
+}
+
+// Snapshot records how many consumers each type had, so that the
+// topology of an Emitter can be verified after it is set up again,
+// such as after a reload.
+type Snapshot = cm.Snapshot
+
+// Export returns a Snapshot of consumers currently registered with e.
+// Consumers registered with OnAll are not counted.
+func Export(e *Emitter) Snapshot {
+	return cm.Export(e)
+}
+
+// ErrMixedPointers is returned by CheckPointers when both T and *T
+// have consumers.
+var ErrMixedPointers = cm.ErrMixedPointers
+
+// CheckPointers returns an error wrapping ErrMixedPointers if some type
+// T and *T both have consumers, which is likely a mistake, as values
+// emitted as one of them never reach consumers of the other. It is meant
+// to be called from tests once an Emitter is set up.
+func CheckPointers(e *Emitter) error {
+	return cm.CheckPointers(e)
+}
@@ -0,0 +1,16 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// Join combines offs into a single off, which calls all of them and
+// returns a chan which will get closed once all of them are done.
+// Together with On it allows one handler to consume several types,
+// while each of them is still dispatched in a type-safe manner:
+//
+//	off := mint.Join(
+//		mint.On(e, func(v StartEvent) { log(v) }),
+//		mint.On(e, func(v StopEvent) { log(v) }),
+//	)
+func Join(offs ...func() <-chan struct{}) (off func() <-chan struct{}) {
+	return cm.Join(offs...)
+}
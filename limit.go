@@ -0,0 +1,54 @@
+package mint
+
+import (
+	"context"
+	"errors"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// ErrTooManySubscribers is returned by TryOn when T
+// already has as many consumers as SetMaxSubscribers allows.
+var ErrTooManySubscribers = cm.ErrTooManySubscribers
+
+// SetMaxSubscribers limits the number of consumers of T to n, so that
+// consumers which are forgotten to be unsubscribed surface early. Once T
+// has n consumers, further On and its variants return off which does
+// nothing, while TryOn returns an error wrapping ErrTooManySubscribers.
+// Consumers which are already registered are kept. Using n < 0 removes
+// the limit, which is the default.
+func SetMaxSubscribers[T any](e *Emitter, n int) {
+	cm.SetMaxSubscribers[T](e, n)
+}
+
+// TryOn registers a new consumer like On, but returns an error if it
+// can't be registered, which is ErrNilEmitter, ErrClosed or an error
+// wrapping ErrTooManySubscribers. Returned off does nothing in that case.
+func TryOn[T any](e *Emitter, fn func(T)) (off func() <-chan struct{}, err error) {
+	return cm.TryOn(e, func(_ context.Context, v T) { fn(v) })
+}
+
+// ErrFanout is what emits of types which have more consumers
+// than SetMaxFanout allows panic with.
+var ErrFanout = cm.ErrFanout
+
+// SetMaxFanout makes emits of types which have more than n consumers panic
+// with an error wrapping ErrFanout, without calling plugins or consumers,
+// as a guard against accidental broadcast storms. Emit variants which
+// return an error return it instead. EmitAsync and EmitPool don't panic,
+// as the emit is already under way once the limit is checked, but drop
+// such values, passing them to the dead letter handler if one is set.
+// Unlike SetMaxSubscribers, it applies to all types and doesn't stop
+// consumers from registering.
+// Using n <= 0 removes the limit, which is the default.
+func SetMaxFanout(e *Emitter, n int) {
+	cm.SetMaxFanout(e, n)
+}
+
+// fanout panics if err is caused by SetMaxFanout, for emits
+// which can't return it.
+func fanout(err error) {
+	if errors.Is(err, ErrFanout) {
+		panic(err)
+	}
+}
@@ -0,0 +1,18 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// Logger receives debug records of an Emitter. *slog.Logger
+// implements it, as do loggers with a similar Debug method.
+type Logger = cm.Logger
+
+// SetLogger makes Emitter log at debug level when consumers are added
+// or removed, when emits start and finish, and when panics of consumers
+// are recovered by the panic handler. Records carry the type name along
+// with the number of consumers. l is called synchronously, sometimes
+// while holding e, so it must not use e.
+//
+// Using nil l stops logging, which is the default.
+func SetLogger(e *Emitter, l Logger) {
+	cm.SetLogger(e, l)
+}
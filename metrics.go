@@ -0,0 +1,13 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// Metrics receives measurements of emits. Its methods are called
+// synchronously by Emit, so they should return quickly.
+type Metrics = cm.Metrics
+
+// SetMetrics makes Emitter report emits to m. Using nil m
+// stops reporting, which is the default.
+func SetMetrics(e *Emitter, m Metrics) {
+	cm.SetMetrics(e, m)
+}
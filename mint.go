@@ -4,6 +4,13 @@
 //	e := new(mint.Emitter) // create an emitter
 //	mint.On(e, func(MyEvent)) // subscribe to MyEvent
 //	mint.Emit(e, MyEvent{ ... }) // emit values to consumers
+//
+// Emits of this package always start with context.Background(), and
+// consumers don't receive a context, so features which are carried by
+// the context of an emit are only available with mint/context. Most
+// notably, SetMaxDepth doesn't see cascades of emits made through
+// this package, as nested emits can't be told apart from concurrent
+// ones without the context.
 package mint
 
 import (
@@ -13,32 +20,276 @@ import (
 )
 
 // Emitter holds all active consumers and Emit hooks.
+// The zero Emitter is ready to use. An Emitter must not be copied,
+// so it should be passed around as *Emitter; go vet reports copies.
+//
+// A single lock guards the Emitter, which emits only hold while looking
+// consumers up, so consumers of one type can come and go without waiting
+// for emits of others to finish. Waiting On and off calls keep new emits
+// from taking the lock, so they take effect promptly even while values
+// are emitted continuously. As that lock is held so briefly, it isn't
+// split by type: slow emits of one type don't slow down On and off of
+// another, so there is little to gain from the extra bookkeeping.
 type Emitter = cm.Emitter
 
+// NewOrderedEmitter creates an Emitter which calls consumers of
+// each type in order they were registered in, instead of an undefined one.
+// Order of remaining consumers is kept as others unsubscribe.
+func NewOrderedEmitter() *Emitter {
+	return cm.NewOrderedEmitter()
+}
+
+// NewSerialEmitter creates an Emitter which delivers all values by a single
+// goroutine, strictly in order they were emitted in, so that consumers never
+// run concurrently with each other and see values of concurrent Emits one
+// after another. This trades throughput for ordering.
+//
+// Emits return once their value is queued, before it is delivered, so they
+// can't report errors of consumers and EmitN reports 0 consumers. Drain
+// waits for queued values to be delivered. Consumers are free to emit, in
+// which case the value is queued after ones which are already queued.
+// EmitOne and EmitGroup deliver values right away.
+//
+// The goroutine is stopped by Close, dropping values which are still queued.
+// Chans returned by EmitAsync and EmitPool for them get closed, and
+// EmitPooled hands them to put.
+func NewSerialEmitter() *Emitter {
+	return cm.NewSerialEmitter()
+}
+
 // Emit Sequentially pushes value v to all consumers of type T.
-// Receive order is indetermenistic.
+// Receive order is indetermenistic. Consumers are free to call On
+// or off, as they are called without holding the Emitter.
 func Emit[T any](e *Emitter, v T) {
-	_ = cm.Emit(e, context.Background(), v)
+	fanout(cm.Emit(e, context.Background(), v))
+}
+
+// ErrNilEmitter is returned by EmitStrict when called with nil Emitter.
+var ErrNilEmitter = cm.ErrNilEmitter
+
+// EmitStrict works like Emit, but returns ErrNilEmitter if e is nil
+// instead of doing nothing, to catch emitters left uninitialized.
+// Otherwise it returns ErrClosed if e is closed, or an error returned
+// by a guard plugin.
+func EmitStrict[T any](e *Emitter, v T) error {
+	return cm.EmitStrict(e, context.Background(), v)
+}
+
+// EmitN works like Emit, but also returns the number
+// of consumers which were called.
+func EmitN[T any](e *Emitter, v T) int {
+	n, err := cm.EmitN(e, context.Background(), v)
+	fanout(err)
+	return n
+}
+
+// EmitAll works like Emit, but pushes all of vs in order, looking
+// consumers up only once. Each value is pushed to all consumers before
+// the next one is, and plugins are called for each value separately.
+// A guard plugin stops emitting further values.
+func EmitAll[T any](e *Emitter, vs []T) {
+	fanout(cm.EmitAll(e, context.Background(), vs))
+}
+
+// EmitErr works like Emit, but collects all non-nil errors returned
+// by consumers registered with OnE and joins them using errors.Join.
+func EmitErr[T any](e *Emitter, v T) error {
+	return cm.EmitErr(e, context.Background(), v)
+}
+
+// EmitUntilErr works like Emit, but stops calling consumers as soon as
+// one registered with OnE returns a non-nil error, and returns it.
+//
+// Consumers with higher priority are called first, while ones with equal
+// priority are called in an undefined order unless the Emitter is
+// ordered, so which consumers are skipped is only defined for them.
+func EmitUntilErr[T any](e *Emitter, v T) error {
+	return cm.EmitUntilErr(e, context.Background(), v)
 }
 
 // On Registers a new consumer that receives all values which were
 // emitted as T. So that On(e, func(any)) will
 // receive all values emitted with Emit[any](e, ...)
 //
-// Call to off schedules consumer to stop once all concurrent Emits stop
-// and returns a <-chan which will get closed once it is done.
-// It is possible for consumer to receive values after a call to stop if
-// other concurrent emits are ongoing.
+// Consumer is registered before On returns, so any Emit which starts
+// after that, including ones from other goroutines which synchronize
+// with the call, delivers values to it.
+//
+// Call to off removes consumer before returning and returns a chan which
+// is already closed. Each Emit works with consumers that were registered
+// when it started, but skips ones which were removed before it got to them,
+// so consumer may only receive values after a call to off from concurrent
+// emits which got to it just before.
+//
+// A consumer may call its own off to stop after the current value,
+// in which case it is gone before the next Emit starts.
+//
+// On with nil Emitter, as well as its variants, does nothing and returns
+// off which does nothing, matching Emit.
 func On[T any](e *Emitter, fn func(T)) (off func() <-chan struct{}) {
 	return cm.On(e, func(_ context.Context, v T) { fn(v) })
 }
 
-// Use allows to hook into event emitting process. Plugins are
-// called sequentially in order they were added to Emitter.
-// Plugin is a function that takes Emitted values and
-// returns nil or a function that will be called after
-// all consumers got the Emitted value. Returned functions
-// are called in reverse order via `defer` statement.
-func Use(e *Emitter, plugin func(any) func()) {
-	cm.Use(e, func(_ context.Context, v any) func() { return plugin(v) })
+// OnP registers a new consumer like On, but with given priority.
+// During Emit consumers with higher priority are called before those
+// with lower one. Consumers registered with On have priority of 0.
+//
+// Relative order of consumers with equal priority is undefined,
+// unless the Emitter is ordered.
+func OnP[T any](e *Emitter, priority int, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnP(e, priority, func(_ context.Context, v T) { fn(v) })
+}
+
+// OnFilter registers a new consumer like On, but fn is only
+// called with values for which pred returns true.
+func OnFilter[T any](e *Emitter, pred func(T) bool, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnFilter(e,
+		func(_ context.Context, v T) bool { return pred(v) },
+		func(_ context.Context, v T) { fn(v) },
+	)
+}
+
+// OnDedup registers a new consumer like On, but fn is not called
+// with values equal to the last one it was called with. The first
+// value is always passed to fn. Concurrent emits are compared with
+// each other in order they reach the consumer.
+func OnDedup[T comparable](e *Emitter, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnDedup(e, func(_ context.Context, v T) { fn(v) })
+}
+
+// OnE registers a new consumer like On, but fn is allowed to
+// return an error. Errors are collected by EmitErr and are
+// discarded by other Emit variants.
+func OnE[T any](e *Emitter, fn func(T) error) (off func() <-chan struct{}) {
+	return cm.OnE(e, func(_ context.Context, v T) error { return fn(v) })
+}
+
+// OnCtx registers a new consumer like On, which unsubscribes once ctx is
+// done. Until then, a goroutine waits for ctx, which exits on ctx
+// being done, a call to off or e being closed.
+func OnCtx[T any](e *Emitter, ctx context.Context, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnCtx(e, ctx, func(_ context.Context, v T) { fn(v) })
+}
+
+// OffSync calls off and waits until it is done. As Emits don't hold
+// on to the Emitter while calling consumers, off removes the consumer
+// right away, without starting a goroutine. It waits only for other
+// calls to On, off and similar, so shutdown is never held up by slow
+// consumers and doesn't need a timeout.
+func OffSync(off func() <-chan struct{}) {
+	cm.OffSync(off)
+}
+
+// Once registers a new consumer that receives only the first value
+// emitted as T and unsubscribes afterwards. Only one of concurrent
+// Emits gets to call fn.
+//
+// Calling off before any value is emitted cancels the subscription.
+func Once[T any](e *Emitter, fn func(T)) (off func() <-chan struct{}) {
+	return cm.Once(e, func(_ context.Context, v T) { fn(v) })
+}
+
+// OnUntil registers a new consumer that receives values emitted as T
+// until fn returns true, unsubscribing afterwards. Calls to fn from
+// concurrent Emits are serialized, so it is never called again once
+// it returns true.
+//
+// Calling off before that cancels the subscription.
+func OnUntil[T any](e *Emitter, fn func(T) (stop bool)) (off func() <-chan struct{}) {
+	return cm.OnUntil(e, func(_ context.Context, v T) bool { return fn(v) })
+}
+
+// Wait blocks until next value is emitted as T and returns it.
+// If ctx is cancelled first, zero value and ctx.Err() are returned.
+func Wait[T any](e *Emitter, ctx context.Context) (T, error) {
+	return cm.Wait[T](e, ctx)
+}
+
+// Overflow tells a buffered consumer what to do with a value
+// when its buffer is full.
+type Overflow = cm.Overflow
+
+const (
+	// DropNewest discards the value being delivered.
+	DropNewest = cm.DropNewest
+	// Block waits for the buffer to free up, stalling the Emit.
+	Block = cm.Block
+	// DropOldest discards the oldest buffered value to make
+	// room for the value being delivered.
+	DropOldest = cm.DropOldest
+)
+
+// OnChan registers a new consumer that forwards all values emitted as T
+// to a chan with given buffer size. Once the buffer is full, policy decides
+// what happens to further values. Using DropOldest with unbuffered
+// chan stalls the Emit until ch is received from.
+//
+// Call to off unsubscribes and closes ch once emits which are already
+// sending to it are done, so that a reader receives every value Emit
+// handed to the consumer and then sees ch closed. Returned chan is
+// closed after ch is. With Block and DropOldest this requires ch to be
+// received from until it is closed, as such emits wait for the reader.
+// Closing e calls off as well.
+func OnChan[T any](e *Emitter, buffer int, policy Overflow) (ch <-chan T, off func() <-chan struct{}) {
+	return cm.OnChan[T](e, buffer, policy)
+}
+
+// OnChanErr registers a new consumer like OnChan with DropNewest, but
+// every time a value is dropped because the buffer is full, a signal is
+// sent to overflow, so that slow readers can be noticed without blocking
+// Emit. Signals are coalesced, so overflow holds at most one of them
+// until it is received from, and it is never closed.
+func OnChanErr[T any](e *Emitter, buffer int) (ch <-chan T, overflow <-chan struct{}, off func() <-chan struct{}) {
+	return cm.OnChanErr[T](e, buffer)
+}
+
+// Count returns the number of consumers currently registered for T.
+func Count[T any](e *Emitter) int {
+	return cm.Count[T](e)
+}
+
+// HasSubscribers reports whether any consumers are currently registered
+// for T. Emits of T without consumers don't allocate, however plugins,
+// taps, metrics and history still see them, so callers may check it to
+// avoid building values nobody receives.
+func HasSubscribers[T any](e *Emitter) bool {
+	return cm.HasSubscribers[T](e)
+}
+
+// Clear unsubscribes all consumers of all types and drops all retained
+// values. Emits which are already in progress may still deliver values
+// to removed consumers.
+func Clear(e *Emitter) {
+	cm.Clear(e)
+}
+
+// ClearType unsubscribes all consumers of T and drops values retained
+// for it. Emits which are already in progress may still deliver values
+// to removed consumers.
+func ClearType[T any](e *Emitter) {
+	cm.ClearType[T](e)
+}
+
+// OffType unsubscribes all consumers of T, except for the one with ID
+// except, if there is one, so that a consumer can take over a value by
+// removing the others. Use 0 to remove all of them. As with off, emits
+// which are already in progress skip removed consumers they didn't get
+// to yet, so it is safe to call from consumers. Unlike ClearType, values
+// retained for T are kept.
+func OffType[T any](e *Emitter, except uint64) {
+	cm.OffType[T](e, except)
+}
+
+// PanicHandler receives panics of consumers, along with
+// the consumer which panicked and the value it was called with.
+type PanicHandler = cm.PanicHandler
+
+// SetPanicHandler makes Emit recover consumers which panic and report
+// them along with recovered value and the emitted value v to h, after
+// which emitting continues with the next consumer. If h itself panics,
+// the panic propagates to the caller of Emit.
+//
+// Without a handler (or with nil h) panics are not recovered.
+func SetPanicHandler(e *Emitter, h PanicHandler) {
+	cm.SetPanicHandler(e, h)
 }
@@ -2,12 +2,19 @@ package mint_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/btvoidx/mint"
 	ctxmint "github.com/btvoidx/mint/context"
+	"github.com/btvoidx/mint/minttest"
 )
 
 type event struct {
@@ -76,6 +83,62 @@ func TestBroadReceiverMisfire(t *testing.T) {
 	mint.Emit(e, event{})
 }
 
+func TestOnAll(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []any
+	off := mint.OnAll(e, func(v any) { got = append(got, v) })
+	mint.On(e, func(v any) { got = append(got, "any") })
+
+	mint.Emit(e, 1)
+	mint.Emit(e, "a")
+	mint.Emit[any](e, event{})
+	mint.EmitOne(e, 2)
+	mint.OffSync(off)
+	mint.Emit(e, 3)
+
+	if fmt.Sprint(got) != "[1 a any { }]" {
+		t.Fatalf("expected catch-all to receive emits of all types; got %v", got)
+	}
+}
+
+func TestExport(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.On(e, func(event) {})
+	mint.On(e, func(event) {})
+	mint.On(e, func(int) {})
+	s := mint.Export(e)
+
+	reloaded := new(mint.Emitter)
+	mint.On(reloaded, func(event) {})
+	mint.On(reloaded, func(int) {})
+	mint.On(reloaded, func(string) {})
+
+	missing := s.Missing(reloaded)
+	if len(missing) != 1 || missing[reflect.TypeOf(event{})] != 1 {
+		t.Fatalf("expected one consumer of event to be missing; got %v", missing)
+	}
+	if missing := s.Missing(e); len(missing) != 0 {
+		t.Fatalf("expected nothing to be missing; got %v", missing)
+	}
+}
+
+func TestCheckPointers(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.On(e, func(event) {})
+	mint.On(e, func(*int) {})
+	if err := mint.CheckPointers(e); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+
+	mint.On(e, func(*event) {})
+	err := mint.CheckPointers(e)
+	if !errors.Is(err, mint.ErrMixedPointers) || !strings.Contains(err.Error(), "mint_test.event") {
+		t.Fatalf("expected %v for mint_test.event; got %v", mint.ErrMixedPointers, err)
+	}
+}
+
 func TestOffSimple(t *testing.T) {
 	e := new(mint.Emitter)
 
@@ -113,6 +176,32 @@ func TestContextNoEmitter(t *testing.T) {
 	}
 }
 
+func TestEmitStrict(t *testing.T) {
+	if err := mint.EmitStrict(nil, event{}); err != mint.ErrNilEmitter {
+		t.Fatalf("expected %v; got %v", mint.ErrNilEmitter, err)
+	}
+	if err := mint.EmitStrict(new(mint.Emitter), event{}); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+}
+
+func TestNilEmitter(t *testing.T) {
+	var e *mint.Emitter
+
+	<-mint.On(e, func(event) { t.Error("consumer of nil emitter called") })()
+	<-mint.Use(e, func(any) func() { t.Error("plugin of nil emitter called"); return nil })()
+	mint.Emit(e, event{})
+
+	if _, err := mint.TryOn(e, func(event) {}); err != mint.ErrNilEmitter {
+		t.Fatalf("expected %v; got %v", mint.ErrNilEmitter, err)
+	}
+	ch, off := mint.OnChan[event](e, 1, mint.DropNewest)
+	<-off()
+	if _, ok := <-ch; ok {
+		t.Fatal("expected chan of nil emitter to be closed")
+	}
+}
+
 func TestUse(t *testing.T) {
 	e := new(mint.Emitter)
 
@@ -130,3 +219,2993 @@ func TestUse(t *testing.T) {
 		t.Fatalf("plugin was called in incorrect order: expected %v, got %v", []int{1, 2, 3}, s)
 	}
 }
+
+func TestUseOrder(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	mint.Use(e, func(any) func() {
+		got = append(got, "1.before")
+		return func() { got = append(got, "1.after") }
+	})
+	mint.Use(e, func(any) func() {
+		got = append(got, "2.before")
+		return nil
+	})
+	mint.Use(e, func(any) func() {
+		got = append(got, "3.before")
+		return func() { got = append(got, "3.after") }
+	})
+	mint.On(e, func(event) { got = append(got, "consumer") })
+
+	mint.Emit(e, event{})
+
+	want := "[1.before 2.before 3.before consumer 3.after 1.after]"
+	if fmt.Sprint(got) != want {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestUseP(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	plugin := func(name string) func(any) func() {
+		return func(any) func() {
+			got = append(got, name)
+			return nil
+		}
+	}
+	mint.Use(e, plugin("a"))
+	mint.UseP(e, -1, plugin("last"))
+	mint.Use(e, plugin("b"))
+	mint.UseP(e, 1, plugin("first"))
+
+	mint.Emit(e, event{})
+
+	if fmt.Sprint(got) != "[first a b last]" {
+		t.Fatalf("expected plugins in order of priority; got %v", got)
+	}
+}
+
+func TestEmitAsync(t *testing.T) {
+	e := new(mint.Emitter)
+
+	// each consumer waits for the other one, so this only
+	// finishes if they are called concurrently
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		mint.On(e, func(event) {
+			wg.Done()
+			wg.Wait()
+		})
+	}
+
+	select {
+	case <-mint.EmitAsync(e, event{}):
+	case <-time.After(time.Second):
+		t.Fatalf("consumers were not called concurrently")
+	}
+}
+
+func TestEmitAsyncPanic(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var i atomic.Uint32
+	mint.On(e, func(event) { panic("oops") })
+	mint.On(e, func(event) { i.Add(1) })
+	mint.On(e, func(event) { i.Add(1) })
+
+	<-mint.EmitAsync(e, event{})
+
+	if i := i.Load(); i != 2 {
+		t.Fatalf("expected 2 consumers to finish; got %d", i)
+	}
+}
+
+func TestEmitAsyncSubscribe(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.On(e, func(event) {
+		off := mint.On(e, func(event) {})
+		<-off()
+	})
+
+	select {
+	case <-mint.EmitAsync(e, event{}):
+	case <-time.After(time.Second):
+		t.Fatalf("deadlocked subscribing from consumer")
+	}
+}
+
+func TestOnce(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var i atomic.Uint32
+	off := mint.Once(e, func(event) { i.Add(1) })
+
+	var wg sync.WaitGroup
+	for j := 0; j < 100; j++ {
+		wg.Add(1)
+		go func() {
+			mint.Emit(e, event{})
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	<-off()
+
+	if i := i.Load(); i != 1 {
+		t.Fatalf("expected consumer to be called once; got %d", i)
+	}
+}
+
+func TestOnceOff(t *testing.T) {
+	e := new(mint.Emitter)
+
+	off := mint.Once(e, func(event) { t.Error("consumer called after off") })
+	<-off()
+
+	mint.Emit(e, event{})
+}
+
+func TestOnChan(t *testing.T) {
+	e := new(mint.Emitter)
+
+	ch, off := mint.OnChan[int](e, 2, mint.DropNewest)
+	mint.Emit(e, 1)
+	mint.Emit(e, 2)
+	mint.Emit(e, 3) // dropped
+	<-off()
+
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("expected %v; got %v", []int{1, 2}, got)
+	}
+}
+
+func TestOnChanBlock(t *testing.T) {
+	e := new(mint.Emitter)
+
+	ch, off := mint.OnChan[int](e, 0, mint.Block)
+	defer off()
+
+	go mint.Emit(e, 1)
+
+	select {
+	case v := <-ch:
+		if v != 1 {
+			t.Fatalf("expected %d; got %d", 1, v)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive")
+	}
+}
+
+func TestOnChanErr(t *testing.T) {
+	e := new(mint.Emitter)
+
+	ch, overflow, off := mint.OnChanErr[int](e, 1)
+	defer off()
+
+	mint.Emit(e, 1)
+	select {
+	case <-overflow:
+		t.Fatal("expected no overflow while buffer has room")
+	default:
+	}
+
+	mint.Emit(e, 2) // dropped
+	mint.Emit(e, 3) // dropped, coalesced
+	select {
+	case <-overflow:
+	default:
+		t.Fatal("expected overflow once buffer is full")
+	}
+	select {
+	case <-overflow:
+		t.Fatal("expected overflow signals to be coalesced")
+	default:
+	}
+
+	if v := <-ch; v != 1 {
+		t.Fatalf("expected %d; got %d", 1, v)
+	}
+}
+
+// waiting is a context which notes the first call to Done, which
+// consumers registered with OnChan make once they wait on a full buffer.
+type waiting struct {
+	context.Context
+	once  sync.Once
+	begun chan struct{}
+}
+
+func (c *waiting) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.begun) })
+	return c.Context.Done()
+}
+
+func TestOnChanOff(t *testing.T) {
+	e := new(mint.Emitter)
+
+	ch, off := ctxmint.OnChan[int](e, 1, mint.Block)
+	mint.Emit(e, 1)
+	ctx := &waiting{Context: context.Background(), begun: make(chan struct{})}
+	go ctxmint.Emit(e, ctx, 2) // waits for 1 to be received
+	<-ctx.begun
+	done := off()
+
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+
+	if fmt.Sprint(got) != "[1 2]" {
+		t.Fatalf("expected %v; got %v", []int{1, 2}, got)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected off to be done once ch is closed")
+	}
+}
+
+func TestOnDedup(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	mint.OnDedup(e, func(v string) { got = append(got, v) })
+	for _, v := range []string{"", "", "a", "a", "b", "a"} {
+		mint.Emit(e, v)
+	}
+
+	if fmt.Sprintf("%q", got) != `["" "a" "b" "a"]` {
+		t.Fatalf("expected consecutive duplicates to be skipped; got %q", got)
+	}
+}
+
+func TestCount(t *testing.T) {
+	e := new(mint.Emitter)
+
+	if c := mint.Count[event](nil); c != 0 {
+		t.Fatalf("expected nil emitter to have %d consumers; got %d", 0, c)
+	}
+
+	off1 := mint.On(e, func(event) {})
+	off2 := mint.On(e, func(event) {})
+	mint.On(e, func(int) {})
+
+	if c := mint.Count[event](e); c != 2 {
+		t.Fatalf("expected %d consumers; got %d", 2, c)
+	}
+
+	<-off1()
+	<-off2()
+	if c := mint.Count[event](e); c != 0 {
+		t.Fatalf("expected %d consumers; got %d", 0, c)
+	}
+}
+
+func TestClear(t *testing.T) {
+	mint.Clear(nil)
+	mint.Clear(new(mint.Emitter))
+
+	e := new(mint.Emitter)
+	mint.On(e, func(event) { t.Error("consumer called after Clear") })
+	mint.On(e, func(int) { t.Error("consumer called after Clear") })
+	mint.Clear(e)
+
+	mint.Emit(e, event{})
+	mint.Emit(e, 1)
+
+	received := false
+	mint.On(e, func(event) { received = true })
+	mint.Emit(e, event{})
+	if !received {
+		t.Fatalf("didn't receive after Clear")
+	}
+}
+
+func TestClearType(t *testing.T) {
+	e := new(mint.Emitter)
+
+	received := false
+	mint.On(e, func(event) { t.Error("consumer called after ClearType") })
+	mint.On(e, func(int) { received = true })
+	mint.ClearType[event](e)
+
+	mint.Emit(e, event{})
+	mint.Emit(e, 1)
+
+	if !received {
+		t.Fatalf("other type was cleared")
+	}
+}
+
+func TestOffKeepsOthers(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var a, b, c int
+	mint.On(e, func(v int) { a = v })
+	off := mint.On(e, func(v int) { b = v })
+	mint.On(e, func(v int) { c = v })
+
+	<-off()
+	mint.Emit(e, 1)
+
+	if a != 1 || b != 0 || c != 1 {
+		t.Fatalf("expected remaining consumers to receive; got a=%d b=%d c=%d", a, b, c)
+	}
+}
+
+func TestEmitErr(t *testing.T) {
+	e := new(mint.Emitter)
+
+	err1, err2 := errors.New("first"), errors.New("second")
+	mint.OnE(e, func(event) error { return err1 })
+	mint.OnE(e, func(event) error { return nil })
+	mint.OnE(e, func(event) error { return err2 })
+	mint.On(e, func(event) {})
+
+	err := mint.EmitErr(e, event{})
+	if !errors.Is(err, err1) || !errors.Is(err, err2) {
+		t.Fatalf("expected both errors to be joined; got %v", err)
+	}
+
+	if err := mint.EmitErr(e, 1); err != nil {
+		t.Fatalf("expected nil error; got %v", err)
+	}
+}
+
+func TestEmitErrContextCancel(t *testing.T) {
+	e := new(mint.Emitter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errConsumer := errors.New("consumer")
+	ctxmint.OnE(e, func(context.Context, event) error {
+		cancel()
+		return errConsumer
+	})
+	ctxmint.OnE(e, func(context.Context, event) error {
+		cancel()
+		return errConsumer
+	})
+
+	err := ctxmint.EmitErr(e, ctx, event{})
+	if !errors.Is(err, errConsumer) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected consumer error and context.Canceled; got %v", err)
+	}
+}
+
+func TestPanicHandler(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var recovered []any
+	mint.SetPanicHandler(e, func(_ mint.Consumer, r any, v any) { recovered = append(recovered, r) })
+
+	received := 0
+	mint.On(e, func(int) { panic("oops") })
+	mint.On(e, func(int) { received++ })
+	mint.On(e, func(int) { received++ })
+
+	mint.Emit(e, 1)
+
+	if len(recovered) != 1 || recovered[0] != "oops" {
+		t.Fatalf("expected handler to receive %q; got %v", "oops", recovered)
+	}
+	if received != 2 {
+		t.Fatalf("expected %d consumers to receive; got %d", 2, received)
+	}
+}
+
+func TestPanicHandlerPanic(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.SetPanicHandler(e, func(_ mint.Consumer, r any, v any) { panic(r) })
+	mint.On(e, func(int) { panic("oops") })
+
+	func() {
+		defer func() {
+			if r := recover(); r != "oops" {
+				t.Errorf("expected panic %q; got %v", "oops", r)
+			}
+		}()
+		mint.Emit(e, 1)
+	}()
+
+	// lock must have been released
+	mint.ClearType[int](e)
+}
+
+func TestNoPanicHandler(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.On(e, func(int) { panic("oops") })
+
+	defer func() {
+		if r := recover(); r != "oops" {
+			t.Errorf("expected panic %q; got %v", "oops", r)
+		}
+	}()
+	mint.Emit(e, 1)
+}
+
+func TestOrdered(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got []int
+	offs := make([]func() <-chan struct{}, 0, 10)
+	for i := 0; i < 10; i++ {
+		i := i
+		offs = append(offs, mint.On(e, func(event) { got = append(got, i) }))
+	}
+	<-offs[3]()
+	<-offs[7]()
+
+	mint.Emit(e, event{})
+
+	want := []int{0, 1, 2, 4, 5, 6, 8, 9}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected order %v; got %v", want, got)
+	}
+}
+
+func TestPriority(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	mint.On(e, func(event) { got = append(got, "default") })
+	mint.OnP(e, -1, func(event) { got = append(got, "audit") })
+	mint.OnP(e, 10, func(event) { got = append(got, "security") })
+
+	mint.Emit(e, event{})
+
+	want := []string{"security", "default", "audit"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected order %v; got %v", want, got)
+	}
+}
+
+func TestPriorityOrdered(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got []int
+	mint.OnP(e, 1, func(event) { got = append(got, 1) })
+	mint.On(e, func(event) { got = append(got, 3) })
+	mint.OnP(e, 1, func(event) { got = append(got, 2) })
+	mint.On(e, func(event) { got = append(got, 4) })
+
+	mint.Emit(e, event{})
+
+	want := []int{1, 2, 3, 4}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected order %v; got %v", want, got)
+	}
+}
+
+func TestWait(t *testing.T) {
+	e := new(mint.Emitter)
+
+	go func() {
+		for mint.Count[int](e) == 0 {
+			runtime.Gosched()
+		}
+		mint.Emit(e, 1)
+	}()
+
+	v, err := mint.Wait[int](e, context.Background())
+	if err != nil || v != 1 {
+		t.Fatalf("expected (%d, nil); got (%d, %v)", 1, v, err)
+	}
+}
+
+func TestWaitCancel(t *testing.T) {
+	e := new(mint.Emitter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	v, err := mint.Wait[int](e, ctx)
+	if err != context.DeadlineExceeded || v != 0 {
+		t.Fatalf("expected (%d, %v); got (%d, %v)", 0, context.DeadlineExceeded, v, err)
+	}
+}
+
+func TestOnFilter(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	mint.OnFilter(e, func(v int) bool { return v%2 == 0 }, func(v int) { got = append(got, v) })
+
+	for i := 0; i < 5; i++ {
+		mint.Emit(e, i)
+	}
+
+	want := []int{0, 2, 4}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestUnuse(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var a, b int
+	unuse := mint.Use(e, func(any) func() { a++; return nil })
+	mint.Use(e, func(any) func() { b++; return nil })
+
+	mint.Emit(e, event{})
+	<-unuse()
+	mint.Emit(e, event{})
+
+	if a != 1 || b != 2 {
+		t.Fatalf("expected plugins to be called (1, 2) times; got (%d, %d)", a, b)
+	}
+}
+
+func TestUseTransform(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.UseTransform(e, func(v any) any {
+		if ev, ok := v.(event); ok {
+			ev.F2 = "<redacted>"
+			return ev
+		}
+		return v
+	})
+
+	var got event
+	mint.On(e, func(v event) { got = v })
+	mint.Emit(e, event{"user", "secret"})
+
+	if got.F1 != "user" || got.F2 != "<redacted>" {
+		t.Fatalf("expected transformed value; got %v", got)
+	}
+}
+
+func TestUseTransformType(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.UseTransform(e, func(v any) any { return "not an event" })
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected Emit to panic")
+		}
+	}()
+	mint.Emit(e, event{})
+}
+
+func TestUseNil(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.Use(e, func(any) func() { return nil })
+
+	received := false
+	mint.On(e, func(v any) { received = v == nil })
+	mint.Emit[any](e, nil)
+
+	if !received {
+		t.Fatalf("didn't receive nil")
+	}
+}
+
+func TestUseGuard(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var s []string
+	errLimited := errors.New("rate limited")
+	mint.Use(e, func(any) func() {
+		s = append(s, "before")
+		return func() { s = append(s, "after") }
+	})
+	mint.UseGuard(e, func(v any) error {
+		if v.(int) > 1 {
+			return errLimited
+		}
+		return nil
+	})
+	mint.Use(e, func(any) func() {
+		s = append(s, "skipped")
+		return nil
+	})
+
+	var got []int
+	mint.On(e, func(v int) { got = append(got, v) })
+
+	if err := mint.EmitErr(e, 1); err != nil {
+		t.Fatalf("expected nil error; got %v", err)
+	}
+	s = s[:0]
+	if err := mint.EmitErr(e, 2); !errors.Is(err, errLimited) {
+		t.Fatalf("expected %v; got %v", errLimited, err)
+	}
+
+	if len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected only %v to be delivered; got %v", []int{1}, got)
+	}
+	if len(s) != 2 || s[0] != "before" || s[1] != "after" {
+		t.Fatalf("expected plugin calls %v; got %v", []string{"before", "after"}, s)
+	}
+}
+
+func TestSticky(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.Sticky[int](e)
+
+	mint.OnSticky(e, func(int) { t.Error("replayed before any emit") })
+	mint.Clear(e)
+
+	mint.Emit(e, 1)
+	mint.Emit(e, 2)
+
+	var got []int
+	mint.OnSticky(e, func(v int) { got = append(got, v) })
+	mint.Emit(e, 3)
+
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("expected %v; got %v", []int{2, 3}, got)
+	}
+
+	mint.ClearType[int](e)
+	mint.OnSticky(e, func(int) { t.Error("replayed after ClearType") })
+}
+
+func TestStickyNilInterface(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.WithHistory[error](e, 2)
+	mint.Emit[error](e, nil)
+
+	var n int
+	mint.Replay(e, func(err error) {
+		if err != nil {
+			t.Errorf("expected nil error; got %v", err)
+		}
+		n += 1
+	})
+	mint.OnSticky(e, func(error) { n += 1 })
+	mint.OnWithReplay(e, 1, func(error) { n += 1 })
+	if n != 3 {
+		t.Fatalf("expected nil to be replayed 3 times; got %d", n)
+	}
+}
+
+func TestOnStickyNotSticky(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.Emit(e, 1)
+	mint.OnSticky(e, func(int) { t.Error("replayed value of non-sticky type") })
+}
+
+func TestHistory(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.WithHistory[int](e, 3)
+
+	for i := 1; i <= 5; i++ {
+		mint.Emit(e, i)
+	}
+
+	var got []int
+	mint.Replay(e, func(v int) { got = append(got, v) })
+	if fmt.Sprint(got) != fmt.Sprint([]int{3, 4, 5}) {
+		t.Fatalf("expected %v; got %v", []int{3, 4, 5}, got)
+	}
+
+	mint.WithHistory[int](e, 2)
+	got = got[:0]
+	mint.Replay(e, func(v int) { got = append(got, v) })
+	if fmt.Sprint(got) != fmt.Sprint([]int{4, 5}) {
+		t.Fatalf("expected %v after resize; got %v", []int{4, 5}, got)
+	}
+
+	last := 0
+	mint.OnSticky(e, func(v int) { last = v })
+	if last != 5 {
+		t.Fatalf("expected OnSticky to replay %d; got %d", 5, last)
+	}
+
+	mint.WithHistory[int](e, 0)
+	mint.Replay(e, func(v int) { t.Errorf("replayed %d after history was disabled", v) })
+}
+
+func TestEmitTimeout(t *testing.T) {
+	e := new(mint.Emitter)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	received := false
+	mint.On(e, func(event) { <-release })
+	mint.On(e, func(event) { <-release })
+	mint.OnP(e, -1, func(event) { received = true })
+
+	err := mint.EmitTimeout(e, event{}, 10*time.Millisecond)
+	if !errors.Is(err, mint.ErrTimeout) {
+		t.Fatalf("expected ErrTimeout; got %v", err)
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 2 {
+		t.Fatalf("expected %d errors; got %d", 2, n)
+	}
+	if !received {
+		t.Fatalf("consumer after slow ones didn't receive")
+	}
+}
+
+func TestEmitTimeoutPanic(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.On(e, func(event) { panic("oops") })
+
+	defer func() {
+		if r := recover(); r != "oops" {
+			t.Errorf("expected panic %q; got %v", "oops", r)
+		}
+	}()
+	_ = mint.EmitTimeout(e, event{}, time.Second)
+}
+
+func TestEmitPool(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var active, peak, total atomic.Int32
+	for i := 0; i < 20; i++ {
+		mint.On(e, func(event) {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			active.Add(-1)
+			total.Add(1)
+		})
+	}
+
+	<-mint.EmitPool(e, event{}, 3)
+
+	if n := total.Load(); n != 20 {
+		t.Fatalf("expected %d consumers to be called; got %d", 20, n)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Fatalf("expected at most %d concurrent consumers; got %d", 3, p)
+	}
+}
+
+func TestClose(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.On(e, func(event) { t.Error("consumer called after Close") })
+	if err := mint.Close(e); err != nil {
+		t.Fatalf("expected nil error; got %v", err)
+	}
+	if err := mint.Close(e); err != mint.ErrClosed {
+		t.Fatalf("expected %v closing twice; got %v", mint.ErrClosed, err)
+	}
+
+	off := mint.On(e, func(event) { t.Error("consumer subscribed after Close") })
+	select {
+	case <-off():
+	default:
+		t.Fatalf("expected off to return closed chan")
+	}
+
+	if err := ctxmint.Emit(e, context.Background(), event{}); err != mint.ErrClosed {
+		t.Fatalf("expected %v; got %v", mint.ErrClosed, err)
+	}
+	<-mint.EmitAsync(e, event{})
+}
+
+func TestCloseStopsConsumers(t *testing.T) {
+	e := new(mint.Emitter)
+
+	n := runtime.NumGoroutine()
+	ch, _ := mint.OnChan[int](e, 4, mint.Block)
+	errCh, _, _ := mint.OnChanErr[string](e, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mint.OnCtx(e, ctx, func(event) {})
+
+	mint.Emit(e, 1)
+	mint.Emit(e, "a")
+	mint.Close(e)
+
+	var got []any
+	for v := range ch {
+		got = append(got, v)
+	}
+	for v := range errCh {
+		got = append(got, v)
+	}
+	if fmt.Sprint(got) != "[1 a]" {
+		t.Fatalf("expected %v; got %v", []any{1, "a"}, got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected goroutines of consumers to be gone; got %d goroutines", runtime.NumGoroutine()-n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// closed emitter gives a closed chan
+	ch, _ = mint.OnChan[int](e, 1, mint.Block)
+	if _, ok := <-ch; ok {
+		t.Fatal("expected chan of closed emitter to be closed")
+	}
+}
+
+func TestOffSync(t *testing.T) {
+	e := new(mint.Emitter)
+
+	off := mint.On(e, func(event) { t.Error("consumer called after OffSync") })
+	n := runtime.NumGoroutine()
+	mint.OffSync(off)
+
+	if c := mint.Count[event](e); c != 0 {
+		t.Fatalf("expected %d consumers; got %d", 0, c)
+	}
+	if m := runtime.NumGoroutine(); m > n {
+		t.Fatalf("expected no goroutines to be started; got %d", m-n)
+	}
+
+	mint.Emit(e, event{})
+}
+
+func TestJoin(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []any
+	log := func(v any) { got = append(got, v) }
+	off := mint.Join(
+		mint.On(e, func(v int) { log(v) }),
+		mint.On(e, func(v string) { log(v) }),
+	)
+
+	mint.Emit(e, 1)
+	mint.Emit(e, "two")
+	<-off()
+	mint.Emit(e, 3)
+	mint.Emit(e, "four")
+
+	if fmt.Sprint(got) != fmt.Sprint([]any{1, "two"}) {
+		t.Fatalf("expected %v; got %v", []any{1, "two"}, got)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.On(e, func(event) {})
+	mint.On(e, func(event) {})
+	mint.On(e, func(error) {})
+	off := mint.On(e, func(int) {})
+	<-off()
+
+	types := mint.Types(e)
+	want := map[reflect.Type]int{
+		reflect.TypeOf(event{}):              2,
+		reflect.TypeOf((*error)(nil)).Elem(): 1,
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected %v; got %v", want, types)
+	}
+}
+
+func TestEmitAll(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got []string
+	mint.On(e, func(v int) { got = append(got, fmt.Sprint("a", v)) })
+	mint.On(e, func(v int) { got = append(got, fmt.Sprint("b", v)) })
+
+	mint.EmitAll(e, []int{1, 2})
+
+	want := []string{"a1", "b1", "a2", "b2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestEmitAllContextCancel(t *testing.T) {
+	e := new(mint.Emitter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []int
+	ctxmint.On(e, func(_ context.Context, v int) {
+		got = append(got, v)
+		if v == 2 {
+			cancel()
+		}
+	})
+
+	err := ctxmint.EmitAll(e, ctx, []int{1, 2, 3})
+	if err != context.Canceled {
+		t.Fatalf("expected %v; got %v", context.Canceled, err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint([]int{1, 2}) {
+		t.Fatalf("expected %v; got %v", []int{1, 2}, got)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.SetMaxDepth(e, 3)
+
+	var i int
+	var err error
+	ctxmint.On(e, func(ctx context.Context, _ event) {
+		i += 1
+		if nested := ctxmint.Emit(e, ctx, event{}); nested != nil {
+			err = nested
+		}
+	})
+
+	if err := ctxmint.Emit(e, context.Background(), event{}); err != nil {
+		t.Fatalf("expected nil error; got %v", err)
+	}
+	if !errors.Is(err, ctxmint.ErrMaxDepthExceeded) {
+		t.Fatalf("expected ErrMaxDepthExceeded; got %v", err)
+	}
+	if i != 3 {
+		t.Fatalf("expected consumer to be called %d times; got %d", 3, i)
+	}
+
+	// cascades of the contextless api are not seen
+	var j int
+	mint.On(e, func(v int) {
+		j += 1
+		if v < 5 {
+			mint.Emit(e, v+1)
+		}
+	})
+	mint.Emit(e, 1)
+	if j != 5 {
+		t.Fatalf("expected consumer to be called %d times; got %d", 5, j)
+	}
+}
+
+func TestUseContextValue(t *testing.T) {
+	e := new(mint.Emitter)
+
+	type reqID struct{}
+	ctxmint.UseContextValue(e, reqID{}, "abc")
+
+	var got any
+	ctxmint.On(e, func(ctx context.Context, _ event) { got = ctx.Value(reqID{}) })
+	ctxmint.Emit(e, context.Background(), event{})
+
+	if got != "abc" {
+		t.Fatalf("expected consumer context to have %q; got %v", "abc", got)
+	}
+}
+
+func TestUseContext(t *testing.T) {
+	e := new(mint.Emitter)
+
+	type span struct{}
+	var s []string
+	ctxmint.Use(e, func(ctx context.Context, _ any) (context.Context, func()) {
+		return context.WithValue(ctx, span{}, "outer"), func() { s = append(s, "end outer") }
+	})
+	ctxmint.Use(e, func(ctx context.Context, _ any) (context.Context, func()) {
+		s = append(s, fmt.Sprint("start inner in ", ctx.Value(span{})))
+		return nil, nil
+	})
+
+	ctxmint.On(e, func(ctx context.Context, _ event) { s = append(s, fmt.Sprint("consume in ", ctx.Value(span{}))) })
+	ctxmint.Emit(e, context.Background(), event{})
+
+	want := []string{"start inner in outer", "consume in outer", "end outer"}
+	if fmt.Sprint(s) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, s)
+	}
+}
+
+func TestUseNamedPluginType(t *testing.T) {
+	e := new(mint.Emitter)
+
+	type plug func(context.Context, any) func()
+	type ctxPlug func(context.Context, any) (context.Context, func())
+	var s []string
+	ctxmint.Use(e, plug(func(context.Context, any) func() {
+		return func() { s = append(s, "plug") }
+	}))
+	ctxmint.UseP(e, -1, ctxPlug(func(context.Context, any) (context.Context, func()) {
+		return nil, func() { s = append(s, "ctxPlug") }
+	}))
+	ctxmint.Emit(e, context.Background(), event{})
+
+	if want := []string{"ctxPlug", "plug"}; fmt.Sprint(s) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, s)
+	}
+}
+
+func TestTypedEmitter(t *testing.T) {
+	var e mint.TypedEmitter[event]
+
+	var got []event
+	off := e.On(func(v event) { got = append(got, v) })
+	e.On(func(v event) { got = append(got, v) })
+
+	if c := e.Count(); c != 2 {
+		t.Fatalf("expected %d consumers; got %d", 2, c)
+	}
+
+	e.Emit(event{"hello", "world"})
+	<-off()
+	e.Emit(event{"bye", "world"})
+
+	if len(got) != 3 {
+		t.Fatalf("expected %d values to be received; got %d", 3, len(got))
+	}
+	if c := e.Count(); c != 1 {
+		t.Fatalf("expected %d consumer; got %d", 1, c)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.On(func(event) { e.On(func(event) {}) })
+		e.Emit(event{"on", "from consumer"})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected consumer to register another one without deadlocking")
+	}
+}
+
+type metrics struct {
+	started, finished []string
+	consumers         int
+}
+
+func (m *metrics) EmitStarted(typeName string) { m.started = append(m.started, typeName) }
+
+func (m *metrics) EmitFinished(typeName string, d time.Duration, consumers int) {
+	m.finished = append(m.finished, typeName)
+	m.consumers += consumers
+}
+
+func TestMetrics(t *testing.T) {
+	e := new(mint.Emitter)
+
+	m := new(metrics)
+	mint.SetMetrics(e, m)
+	mint.On(e, func(event) {})
+	mint.On(e, func(event) {})
+
+	mint.Emit(e, event{})
+	mint.Emit(e, 1)
+	<-mint.EmitAsync(e, event{})
+
+	want := []string{"mint_test.event", "int", "mint_test.event"}
+	if fmt.Sprint(m.started) != fmt.Sprint(want) || fmt.Sprint(m.finished) != fmt.Sprint(want) {
+		t.Fatalf("expected %v to start and finish; got %v and %v", want, m.started, m.finished)
+	}
+	if m.consumers != 4 {
+		t.Fatalf("expected %d consumers to be reported; got %d", 4, m.consumers)
+	}
+}
+
+func TestSubscribeDuringEmit(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var late int
+	var off func() <-chan struct{}
+	off = mint.On(e, func(event) {
+		mint.On(e, func(event) { late += 1 })
+		mint.OffSync(off)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mint.Emit(e, event{})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Emit deadlocked")
+	}
+
+	if late != 0 {
+		t.Fatalf("expected consumer added during Emit not to be called; got %d calls", late)
+	}
+	if c := mint.Count[event](e); c != 1 {
+		t.Fatalf("expected %d consumers; got %d", 1, c)
+	}
+
+	mint.Emit(e, event{})
+	if late != 1 {
+		t.Fatalf("expected consumer to be called %d times; got %d", 1, late)
+	}
+}
+
+func TestOffChurn(t *testing.T) {
+	e := new(mint.Emitter)
+
+	keep := mint.On(e, func(int) {})
+	for i := 0; i < 10000; i++ {
+		mint.OffSync(mint.On(e, func(event) {}))
+		mint.OffSync(mint.On(e, func(int) {}))
+	}
+
+	if types := mint.Types(e); len(types) != 1 || types[reflect.TypeOf(0)] != 1 {
+		t.Fatalf("expected only %d consumer of int to remain; got %v", 1, types)
+	}
+
+	mint.OffSync(keep)
+	if types := mint.Types(e); len(types) != 0 {
+		t.Fatalf("expected no types to remain; got %v", types)
+	}
+}
+
+func TestEmitN(t *testing.T) {
+	e := new(mint.Emitter)
+
+	if n := mint.EmitN(e, event{}); n != 0 {
+		t.Fatalf("expected %d consumers to be called; got %d", 0, n)
+	}
+
+	cancel := func() {}
+	ctxmint.OnP(e, 1, func(context.Context, event) { cancel() })
+	ctxmint.On(e, func(context.Context, event) {})
+
+	if n := mint.EmitN(e, event{}); n != 2 {
+		t.Fatalf("expected %d consumers to be called; got %d", 2, n)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	cancel = stop
+	n, err := ctxmint.EmitN(e, ctx, event{})
+	if n != 1 || err != context.Canceled {
+		t.Fatalf("expected %d consumer and %v; got %d and %v", 1, context.Canceled, n, err)
+	}
+}
+
+func TestOnQueue(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	off := mint.OnQueue(e, 4, mint.Block, func(v int) { got = append(got, v) })
+
+	var want []int
+	for i := 0; i < 100; i++ {
+		mint.Emit(e, i)
+		want = append(want, i)
+	}
+	mint.OffSync(off)
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestOnQueueDropOldest(t *testing.T) {
+	e := new(mint.Emitter)
+
+	started, gate := make(chan struct{}), make(chan struct{})
+	var got []int
+	off := mint.OnQueue(e, 2, mint.DropOldest, func(v int) {
+		if v == 0 {
+			close(started)
+			<-gate
+		}
+		got = append(got, v)
+	})
+
+	mint.Emit(e, 0)
+	<-started
+	for i := 1; i <= 5; i++ {
+		mint.Emit(e, i)
+	}
+	close(gate)
+	mint.OffSync(off)
+
+	if want := []int{0, 4, 5}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestOnQueueClose(t *testing.T) {
+	e := new(mint.Emitter)
+
+	n := runtime.NumGoroutine()
+	var got []int
+	mint.OnQueue(e, 4, mint.Block, func(v int) { got = append(got, v) })
+	mint.Emit(e, 1)
+	mint.Emit(e, 2)
+	mint.Close(e)
+	mint.Drain(e)
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected queue goroutines to be gone; got %d goroutines", runtime.NumGoroutine()-n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if want := []int{1, 2}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+
+	// consumers can't be added to a closed emitter
+	mint.OffSync(mint.OnQueue(e, 4, mint.Block, func(int) {}))
+	if runtime.NumGoroutine() > n {
+		t.Fatalf("expected no goroutines for a closed emitter; got %d", runtime.NumGoroutine()-n)
+	}
+}
+
+func TestOnCtx(t *testing.T) {
+	e := new(mint.Emitter)
+
+	n := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	var got int
+	mint.OnCtx(e, ctx, func(event) { got += 1 })
+	mint.OffSync(mint.OnCtx(e, ctx, func(event) { t.Error("consumer called after off") }))
+
+	mint.Emit(e, event{})
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for mint.Count[event](e) != 0 || runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected consumer and its goroutine to be gone; got %d consumers and %d goroutines",
+				mint.Count[event](e), runtime.NumGoroutine()-n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	mint.Emit(e, event{})
+	if got != 1 {
+		t.Fatalf("expected consumer to be called %d times; got %d", 1, got)
+	}
+}
+
+func TestOnUntil(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	mint.OnUntil(e, func(v int) bool {
+		got = append(got, v)
+		return v == 2
+	})
+	for i := 1; i <= 3; i++ {
+		mint.Emit(e, i)
+	}
+	if want := []int{1, 2}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+
+	var calls atomic.Int32
+	mint.OnUntil(e, func(event) bool { calls.Add(1); return true })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mint.Emit(e, event{})
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected consumer to be called %d times; got %d", 1, n)
+	}
+	if types := mint.Types(e); len(types) != 0 {
+		t.Fatalf("expected no consumers to remain; got %v", types)
+	}
+
+	mint.OffSync(mint.OnUntil(e, func(event) bool { t.Error("consumer called after off"); return false }))
+	mint.Emit(e, event{})
+}
+
+func TestTap(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	untap := mint.Tap(e, func(typeName string, v any) {
+		got = append(got, fmt.Sprintf("%s:%T", typeName, v))
+	})
+
+	mint.Emit(e, 1)
+	mint.Emit[any](e, "a")
+	<-mint.EmitAsync(e, event{})
+	mint.OffSync(untap)
+	mint.Emit(e, 2)
+
+	want := []string{"int:int", "interface {}:string", "mint_test.event:mint_test.event"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestOffAfterClear(t *testing.T) {
+	e := new(mint.Emitter)
+
+	off := mint.On(e, func(event) {})
+	<-off()
+	<-off()
+
+	stale := mint.On(e, func(event) {})
+	mint.Clear(e)
+	keep := mint.On(e, func(event) {})
+	<-stale()
+	<-stale()
+
+	if c := mint.Count[event](e); c != 1 {
+		t.Fatalf("expected %d consumers; got %d", 1, c)
+	}
+
+	stale = mint.On(e, func(int) {})
+	mint.ClearType[int](e)
+	<-stale()
+	<-keep()
+	if types := mint.Types(e); len(types) != 0 {
+		t.Fatalf("expected no types to remain; got %v", types)
+	}
+}
+
+func TestIsolated(t *testing.T) {
+	e := new(mint.Emitter)
+	ctxmint.SetIsolated(e, true)
+
+	var ctxs []context.Context
+	for i := 0; i < 2; i++ {
+		ctxmint.On(e, func(ctx context.Context, _ event) {
+			if ctx.Err() != nil {
+				t.Error("consumer received cancelled context")
+			}
+			ctxs = append(ctxs, ctx)
+		})
+	}
+
+	parent := context.Background()
+	if err := ctxmint.Emit(e, parent, event{}); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+
+	if len(ctxs) != 2 || ctxs[0] == parent || ctxs[0] == ctxs[1] {
+		t.Fatalf("expected %d distinct consumer contexts; got %v", 2, ctxs)
+	}
+	for _, ctx := range ctxs {
+		if ctx.Err() != context.Canceled {
+			t.Fatalf("expected consumer context to be cancelled once it returns; got %v", ctx.Err())
+		}
+	}
+}
+
+func TestEmitDynamic(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []any
+	mint.On(e, func(v event) { got = append(got, v) })
+	mint.On(e, func(v fmt.Stringer) { got = append(got, v) })
+
+	if err := mint.EmitDynamic(e, reflect.TypeOf(event{}), event{}); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+	stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	if err := mint.EmitDynamic(e, stringer, time.Second); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+	if err := mint.EmitDynamic(e, stringer, nil); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+
+	want := []any{event{}, time.Second, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+
+	if err := mint.EmitDynamic(e, reflect.TypeOf(event{}), 1); !errors.Is(err, mint.ErrTypeMismatch) {
+		t.Fatalf("expected %v; got %v", mint.ErrTypeMismatch, err)
+	}
+}
+
+func TestOnSeq(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	mint.OnSeq(e, func(seq uint64, v int) { got = append(got, fmt.Sprintf("%d:%d", seq, v)) })
+	mint.OnSeq(e, func(seq uint64, v string) { got = append(got, fmt.Sprintf("%d:%s", seq, v)) })
+
+	mint.Emit(e, 10)
+	mint.Emit(e, "a")
+	mint.EmitAll(e, []int{20, 30})
+	<-mint.EmitAsync(e, "b")
+
+	want := []string{"1:10", "2:a", "3:20", "4:30", "5:b"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestPause(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var mu sync.Mutex
+	var got []any
+	record := func(v any) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, v)
+	}
+	mint.On(e, func(v int) {
+		record(v)
+		if v == 1 {
+			mint.Emit(e, "nested")
+		}
+	})
+	mint.On(e, func(v string) { record(v) })
+
+	mint.Pause(e)
+	mint.Emit(e, 1)
+	mint.EmitAll(e, []int{2, 3})
+	done := mint.EmitAsync(e, "a")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mint.Emit(e, "b")
+		}()
+	}
+	wg.Wait()
+
+	if len(got) != 0 {
+		t.Fatalf("expected nothing to be delivered while paused; got %v", got)
+	}
+
+	mint.Resume(e)
+	<-done
+	mint.Emit(e, 4)
+
+	want := []any{1, 2, 3, "a", "b", "b", "b", "b", "b", "b", "b", "b", "b", "b", "nested", 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var n atomic.Int32
+	gate := make(chan struct{})
+	mint.On(e, func(event) { <-gate; n.Add(1) })
+	mint.OnQueue(e, 8, mint.Block, func(int) { <-gate; n.Add(1) })
+
+	for i := 0; i < 4; i++ {
+		mint.EmitAsync(e, event{})
+		mint.Emit(e, i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := ctxmint.Drain(e, ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v; got %v", context.DeadlineExceeded, err)
+	}
+
+	close(gate)
+	mint.Drain(e)
+	if got := n.Load(); got != 8 {
+		t.Fatalf("expected %d deliveries to be done; got %d", 8, got)
+	}
+}
+
+func TestDrainAfterPluginPanic(t *testing.T) {
+	e := new(mint.Emitter)
+
+	m := new(metrics)
+	mint.SetMetrics(e, m)
+	mint.On(e, func(int) {})
+	unuse := mint.UseTransform(e, func(any) any { return "not an int" })
+
+	func() {
+		defer func() { _ = recover() }()
+		mint.EmitAsync(e, 1)
+		t.Fatalf("expected EmitAsync to panic")
+	}()
+	<-unuse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := ctxmint.Drain(e, ctx); err != nil {
+		t.Fatalf("expected Drain not to wait for panicked emit; got %v", err)
+	}
+	if len(m.started) != 1 || len(m.finished) != 1 {
+		t.Fatalf("expected 1 started and 1 finished emit; got %v and %v", m.started, m.finished)
+	}
+}
+
+func TestMaxSubscribers(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.SetMaxSubscribers[event](e, 2)
+
+	var got int
+	for i := 0; i < 3; i++ {
+		mint.On(e, func(event) { got += 1 })
+	}
+	if _, err := mint.TryOn(e, func(event) { got += 1 }); !errors.Is(err, mint.ErrTooManySubscribers) {
+		t.Fatalf("expected %v; got %v", mint.ErrTooManySubscribers, err)
+	}
+	if _, err := mint.TryOn(e, func(int) {}); err != nil {
+		t.Fatalf("expected other types not to be limited; got %v", err)
+	}
+
+	mint.Emit(e, event{})
+	if got != 2 {
+		t.Fatalf("expected %d consumers to be called; got %d", 2, got)
+	}
+
+	mint.SetMaxSubscribers[event](e, -1)
+	if _, err := mint.TryOn(e, func(event) {}); err != nil {
+		t.Fatalf("expected no error once limit is removed; got %v", err)
+	}
+}
+
+func TestEmitOne(t *testing.T) {
+	e := new(mint.Emitter)
+
+	if mint.EmitOne(e, 0) {
+		t.Fatal("expected value not to be delivered without consumers")
+	}
+
+	var got []string
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		mint.On(e, func(v int) { got = append(got, fmt.Sprint(name, v)) })
+	}
+	for i := 0; i < 5; i++ {
+		if !mint.EmitOne(e, i) {
+			t.Fatalf("expected value %d to be delivered", i)
+		}
+	}
+
+	if want := []string{"a0", "b1", "c2", "a3", "b4"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestSlowConsumer(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []mint.Consumer
+	mint.SetSlowConsumerHandler(e, 5*time.Millisecond, func(c mint.Consumer, elapsed time.Duration) {
+		if elapsed < 5*time.Millisecond {
+			t.Errorf("expected consumer to be reported after %v; got %v", 5*time.Millisecond, elapsed)
+		}
+		got = append(got, c)
+	})
+
+	mint.On(e, func(event) {})
+	mint.On(e, func(event) { time.Sleep(10 * time.Millisecond) })
+	mint.Emit(e, event{})
+
+	want := []mint.Consumer{{ID: 2, Type: reflect.TypeOf(event{})}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestOnNamed(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var panicked []string
+	mint.SetPanicHandler(e, func(c mint.Consumer, r any, v any) {
+		panicked = append(panicked, fmt.Sprintf("%s %v %v", c.Name, c.Type, r))
+	})
+
+	mint.On(e, func(int) {})
+	mint.OnNamed(e, "cache-invalidator", func(int) { panic("oops") })
+	<-mint.EmitAsync(e, 1)
+	mint.Emit(e, 2)
+
+	want := []string{"cache-invalidator int oops", "cache-invalidator int oops"}
+	if fmt.Sprint(panicked) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, panicked)
+	}
+
+	cs := mint.Consumers(e)
+	wantCs := []mint.Consumer{
+		{ID: 1, Type: reflect.TypeOf(0)},
+		{ID: 2, Type: reflect.TypeOf(0), Name: "cache-invalidator"},
+	}
+	if !reflect.DeepEqual(cs, wantCs) {
+		t.Fatalf("expected %v; got %v", wantCs, cs)
+	}
+}
+
+// group is a minimal errgroup.Group.
+type group struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+func (g *group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+	}()
+}
+
+func TestEmitGroup(t *testing.T) {
+	e := new(mint.Emitter)
+
+	errFailed := errors.New("failed")
+	var n atomic.Int32
+	mint.OnE(e, func(event) error { n.Add(1); return errFailed })
+	mint.OnE(e, func(event) error { n.Add(1); return nil })
+	mint.On(e, func(event) { n.Add(1) })
+
+	g := new(group)
+	mint.EmitGroup(e, g, event{})
+	g.wg.Wait()
+
+	if n.Load() != 3 {
+		t.Fatalf("expected %d consumers to be called; got %d", 3, n.Load())
+	}
+	if len(g.errs) != 1 || g.errs[0] != errFailed {
+		t.Fatalf("expected only %v; got %v", errFailed, g.errs)
+	}
+
+	mint.Close(e)
+	g = new(group)
+	mint.EmitGroup(e, g, event{})
+	g.wg.Wait()
+	if len(g.errs) != 1 || g.errs[0] != mint.ErrClosed {
+		t.Fatalf("expected only %v; got %v", mint.ErrClosed, g.errs)
+	}
+}
+
+type ifaceError struct{}
+
+func (*ifaceError) Error() string { return "iface" }
+
+func TestEmitIface(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got []string
+	mint.On(e, func(*ifaceError) { got = append(got, "exact") })
+	mint.OnIface(e, func(error) { got = append(got, "error") })
+	mint.OnIface(e, func(fmt.Stringer) { got = append(got, "stringer") })
+	mint.OnIface(e, func(any) { got = append(got, "any") })
+
+	mint.EmitIface(e, &ifaceError{})
+	mint.Emit(e, &ifaceError{})
+	mint.EmitIface[error](e, nil)
+
+	want := []string{"exact", "error", "any", "exact", "error", "any"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected OnIface to panic for a non-interface type")
+		}
+	}()
+	mint.OnIface(e, func(int) {})
+}
+
+func TestOffRepeated(t *testing.T) {
+	e := new(mint.Emitter)
+
+	off := mint.On(e, func(event) {})
+	unuse := mint.Use(e, func(any) func() { return nil })
+	n := runtime.NumGoroutine()
+
+	done := off()
+	for i := 0; i < 1000; i++ {
+		if off() != done {
+			t.Fatal("expected off to return the same chan every time")
+		}
+		<-unuse()
+	}
+
+	if m := runtime.NumGoroutine(); m > n {
+		t.Fatalf("expected no goroutines to be started; got %d", m-n)
+	}
+}
+
+func TestUseFor(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	mint.Use(e, func(v any) func() { got = append(got, fmt.Sprint("any ", v)); return nil })
+	mint.UseFor(e, func(v int) func() {
+		got = append(got, fmt.Sprint("int ", v))
+		return func() { got = append(got, "after") }
+	})
+	mint.On(e, func(int) { got = append(got, "consumer") })
+
+	mint.Emit(e, 1)
+	mint.Emit(e, "a")
+	mint.Emit[any](e, 2)
+
+	want := []string{"any 1", "int 1", "consumer", "after", "any a", "any 2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestSerialEmitter(t *testing.T) {
+	e := mint.NewSerialEmitter()
+
+	type step struct{ producer, i int }
+	var active atomic.Int32
+	last := make(map[int]int)
+	mint.On(e, func(s step) {
+		if active.Add(1) > 1 {
+			t.Error("consumers ran concurrently")
+		}
+		defer active.Add(-1)
+
+		if prev, ok := last[s.producer]; ok && prev != s.i-1 {
+			t.Errorf("producer %d: expected %d after %d", s.producer, prev+1, prev)
+		}
+		last[s.producer] = s.i
+		if s.i == 99 {
+			mint.Emit(e, "nested")
+		}
+	})
+	var nested atomic.Int32
+	mint.On(e, func(string) { nested.Add(1) })
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				mint.Emit(e, step{p, i})
+			}
+		}(p)
+	}
+	wg.Wait()
+	mint.Drain(e)
+
+	if len(last) != 4 || nested.Load() != 4 {
+		t.Fatalf("expected all values to be delivered; got %v and %d nested", last, nested.Load())
+	}
+
+	n := runtime.NumGoroutine()
+	mint.Close(e)
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() >= n {
+		if time.Now().After(deadline) {
+			t.Fatal("expected dispatch goroutine to stop on Close")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSerialEmitterClose(t *testing.T) {
+	e := mint.NewSerialEmitter()
+
+	started, gate := make(chan struct{}), make(chan struct{})
+	var got []int
+	mint.On(e, func(v int) {
+		if v == 1 {
+			close(started)
+			<-gate
+		}
+		got = append(got, v)
+	})
+
+	mint.Emit(e, 1)
+	<-started
+	done := mint.EmitAsync(e, 2)
+	put := 0
+	mint.EmitPooled(e, func() int { return 3 }, func(v int) { put = v })
+	mint.Close(e)
+
+	select {
+	case <-done:
+	default:
+		t.Fatal("expected chan of dropped EmitAsync to be closed by Close")
+	}
+	if put != 3 {
+		t.Fatalf("expected dropped value %d to be handed to put; got %d", 3, put)
+	}
+
+	close(gate)
+	mint.Drain(e)
+	if want := []int{1}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestOnH(t *testing.T) {
+	e := new(mint.Emitter)
+
+	a := mint.OnH(e, func(event) {})
+	b := mint.OnH(e, func(int) {})
+	if a.ID() == 0 || b.ID() <= a.ID() {
+		t.Fatalf("expected growing non-zero ids; got %d and %d", a.ID(), b.ID())
+	}
+	if c := b.Consumer(); c.ID != b.ID() || c.Type != reflect.TypeOf(0) {
+		t.Fatalf("expected consumer %d of int; got %v", b.ID(), c)
+	}
+
+	<-a.Off()
+	if cs := mint.Consumers(e); len(cs) != 1 || cs[0].ID != b.ID() {
+		t.Fatalf("expected only consumer %d to remain; got %v", b.ID(), cs)
+	}
+
+	mint.Close(e)
+	if h := mint.OnH(e, func(event) {}); h.ID() != 0 {
+		t.Fatalf("expected id %d for consumer of a closed Emitter; got %d", 0, h.ID())
+	}
+}
+
+func TestOffFromOwnConsumer(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	var off func() <-chan struct{}
+	off = mint.On(e, func(v int) {
+		got = append(got, v)
+		select {
+		case <-off():
+		default:
+			t.Error("expected off to be done before returning")
+		}
+	})
+
+	mint.Emit(e, 1)
+	mint.Emit(e, 2)
+
+	if fmt.Sprint(got) != "[1]" {
+		t.Fatalf("expected consumer to receive only %v; got %v", []int{1}, got)
+	}
+	if c := mint.Count[int](e); c != 0 {
+		t.Fatalf("expected %d consumers; got %d", 0, c)
+	}
+}
+
+func TestHasSubscribers(t *testing.T) {
+	e := new(mint.Emitter)
+
+	if mint.HasSubscribers[int](e) {
+		t.Fatal("expected no subscribers")
+	}
+
+	off := mint.On(e, func(int) {})
+	if !mint.HasSubscribers[int](e) {
+		t.Fatal("expected subscribers after On")
+	}
+	if mint.HasSubscribers[string](e) {
+		t.Fatal("expected no subscribers of other types")
+	}
+
+	mint.OffSync(off)
+	if mint.HasSubscribers[int](e) {
+		t.Fatal("expected no subscribers after off")
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { mint.Emit(e, 1) }); allocs != 0 {
+		t.Fatalf("expected emit without consumers to not allocate; got %v allocs", allocs)
+	}
+}
+
+func BenchmarkEmitNoConsumers(b *testing.B) {
+	e := new(mint.Emitter)
+	mint.On(e, func(string) {})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		mint.Emit(e, i)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	e := new(mint.Emitter)
+	g := mint.NewGroup(e)
+
+	var got []any
+	mint.OnGroup(g, func(v int) { got = append(got, v) })
+	mint.OnGroup(g, func(v string) { got = append(got, v) })
+	g.Add(mint.On(e, func(v bool) { got = append(got, v) }))
+	mint.On(e, func(v int) { got = append(got, -v) })
+
+	mint.Emit(e, 1)
+	<-g.Close()
+	mint.Emit(e, 2)
+	mint.Emit(e, "a")
+	mint.Emit(e, true)
+
+	mint.OnGroup(g, func(v int) { got = append(got, v) })
+	mint.Emit(e, 3)
+
+	if fmt.Sprint(got) != "[1 -1 -2 -3]" && fmt.Sprint(got) != "[-1 1 -2 -3]" {
+		t.Fatalf("expected group consumers to stop after Close; got %v", got)
+	}
+}
+
+func TestEmitDeadline(t *testing.T) {
+	e := new(mint.Emitter)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	var called atomic.Int32
+	ctxmint.OnP(e, 1, func(context.Context, int) { <-release })
+	ctxmint.OnP(e, 0, func(context.Context, int) { called.Add(1) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := ctxmint.Emit(e, ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v; got %v", context.DeadlineExceeded, err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected emit to return by deadline; took %v", d)
+	}
+	if n := called.Load(); n != 0 {
+		t.Fatalf("expected consumers after deadline to not be called; got %d calls", n)
+	}
+}
+
+func TestOnStop(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	mint.OnP(e, 1, func(v int) { got = append(got, 1) })
+	mint.OnStop(e, func(v int, stop func()) {
+		got = append(got, 0)
+		if v < 0 {
+			stop()
+		}
+	})
+	mint.OnP(e, -1, func(v int) { got = append(got, -1) })
+
+	mint.Emit(e, 1)
+	if err := mint.EmitErr(e, -1); err != nil {
+		t.Fatalf("expected stop to not be an error; got %v", err)
+	}
+
+	if fmt.Sprint(got) != "[1 0 -1 1 0]" {
+		t.Fatalf("expected consumers after stop to be skipped; got %v", got)
+	}
+}
+
+func TestEmitUntilErr(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	fail := errors.New("rejected")
+	mint.OnP(e, 2, func(v int) { got = append(got, 2) })
+	mint.OnE(e, func(v int) error {
+		got = append(got, 1)
+		if v < 0 {
+			return fail
+		}
+		return nil
+	})
+	ctxmint.OnP(e, -1, func(context.Context, int) { got = append(got, 0) })
+
+	if err := mint.EmitUntilErr(e, 1); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+	if err := mint.EmitUntilErr(e, -1); err != fail {
+		t.Fatalf("expected %v; got %v", fail, err)
+	}
+	if fmt.Sprint(got) != "[2 1 0 2 1]" {
+		t.Fatalf("expected consumers after the error to be skipped; got %v", got)
+	}
+}
+
+func BenchmarkOnDuringSlowEmit(b *testing.B) {
+	e := new(mint.Emitter)
+	mint.On(e, func(int) { time.Sleep(time.Millisecond) })
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				mint.Emit(e, 1)
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mint.OffSync(mint.On(e, func(string) {}))
+	}
+	b.StopTimer()
+
+	close(stop)
+	<-done
+}
+
+// BenchmarkChurnAgainstSlowEmits compares churn of one type's consumers
+// with and without slow emits of another type running, to show that
+// the single lock of Emitter is not what slows it down.
+func BenchmarkChurnAgainstSlowEmits(b *testing.B) {
+	for _, slow := range []bool{false, true} {
+		name := "idle"
+		if slow {
+			name = "slow emits"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			e := new(mint.Emitter)
+			mint.On(e, func(int) { time.Sleep(time.Millisecond) })
+
+			stop := make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for slow {
+					select {
+					case <-stop:
+						return
+					default:
+						mint.Emit(e, 1)
+					}
+				}
+			}()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				mint.OffSync(mint.On(e, func(string) {}))
+			}
+			b.StopTimer()
+
+			close(stop)
+			<-done
+		})
+	}
+}
+
+type logger struct{ records []string }
+
+func (l *logger) Debug(msg string, args ...any) {
+	l.records = append(l.records, fmt.Sprintf("%s %v=%v", msg, args[0], args[1]))
+}
+
+func TestSetLogger(t *testing.T) {
+	e := new(mint.Emitter)
+	l := new(logger)
+	mint.SetLogger(e, l)
+	mint.SetPanicHandler(e, func(mint.Consumer, any, any) {})
+
+	off := mint.On(e, func(int) { panic("oops") })
+	mint.Emit(e, 1)
+	mint.OffSync(off)
+
+	mint.SetLogger(e, nil)
+	mint.Emit(e, 2)
+
+	want := []string{
+		"mint: consumer added type=int",
+		"mint: emit started type=int",
+		"mint: panic recovered type=int",
+		"mint: emit finished type=int",
+		"mint: consumer removed type=int",
+	}
+	if fmt.Sprint(l.records) != fmt.Sprint(want) {
+		t.Fatalf("expected records %q; got %q", want, l.records)
+	}
+}
+
+func TestOnDuringContinuousEmits(t *testing.T) {
+	e := new(mint.Emitter)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				mint.Emit(e, 1)
+			}
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+
+	for i := 0; i < 100; i++ {
+		got := make(chan struct{}, 1)
+		start := time.Now()
+		off := mint.On(e, func(int) {
+			select {
+			case got <- struct{}{}:
+			default:
+			}
+		})
+
+		select {
+		case <-got:
+		case <-time.After(time.Second):
+			t.Fatalf("consumer %d didn't receive values within %v", i, time.Since(start))
+		}
+		mint.OffSync(off)
+	}
+}
+
+func TestOnVisibleToConcurrentEmit(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		e := new(mint.Emitter)
+
+		var got atomic.Int32
+		registered := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			<-registered
+			mint.Emit(e, i)
+		}()
+
+		mint.On(e, func(int) { got.Add(1) })
+		close(registered)
+		<-done
+
+		if got.Load() != 1 {
+			t.Fatalf("expected emit started after On returned to deliver value; got %d values", got.Load())
+		}
+	}
+}
+
+func TestOffDuringSlowEmit(t *testing.T) {
+	e := new(mint.Emitter)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mint.On(e, func(int) {
+		close(started)
+		<-release
+	})
+	off := mint.On(e, func(string) {})
+
+	go mint.Emit(e, 1)
+	<-started
+	defer close(release)
+
+	select {
+	case <-off():
+	case <-time.After(time.Second):
+		t.Fatal("expected off to not wait for the slow emit")
+	}
+}
+
+func BenchmarkEmitOneConsumer(b *testing.B) {
+	e := new(mint.Emitter)
+	mint.On(e, func(int) {})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		mint.Emit(e, i)
+	}
+}
+
+func TestSetMaxFanout(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.SetMaxFanout(e, 1)
+
+	called := 0
+	mint.On(e, func(int) { called += 1 })
+	mint.Emit(e, 1)
+
+	mint.On(e, func(int) { called += 1 })
+	if err := ctxmint.Emit(e, context.Background(), 2); !errors.Is(err, mint.ErrFanout) {
+		t.Fatalf("expected %v; got %v", mint.ErrFanout, err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected Emit to panic")
+			}
+		}()
+		mint.Emit(e, 3)
+	}()
+
+	dropped := make(chan any, 2)
+	mint.SetDeadLetter(e, 2, func(_ string, v any) { dropped <- v })
+	<-mint.EmitAsync(e, 4)
+	<-mint.EmitPool(e, 5, 2)
+	if a, b := <-dropped, <-dropped; a != 4 || b != 5 {
+		t.Fatalf("expected async emits to drop %v; got %v", []any{4, 5}, []any{a, b})
+	}
+
+	mint.SetMaxFanout(e, 0)
+	mint.Emit(e, 6)
+
+	if called != 3 {
+		t.Fatalf("expected %d calls; got %d", 3, called)
+	}
+}
+
+func TestOnDebounce(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var mu sync.Mutex
+	var got []int
+	off := mint.OnDebounce(e, 20*time.Millisecond, func(v int) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, v)
+	})
+
+	for i := 1; i <= 3; i++ {
+		mint.Emit(e, i)
+	}
+	mint.Drain(e)
+
+	mint.Emit(e, 4)
+	<-off()
+	time.Sleep(40 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(got) != "[3]" {
+		t.Fatalf("expected only the last value of the burst; got %v", got)
+	}
+}
+
+func TestOnDebounceClose(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.OnDebounce(e, 10*time.Millisecond, func(v int) { t.Errorf("pending value %d passed to fn after Close", v) })
+	mint.Emit(e, 1)
+	mint.Close(e)
+	mint.Drain(e)
+}
+
+func TestOnThrottle(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var mu sync.Mutex
+	var got []int
+	off := mint.OnThrottle(e, 20*time.Millisecond, func(v int) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, v)
+	})
+
+	for i := 1; i <= 3; i++ {
+		mint.Emit(e, i)
+	}
+	mint.Drain(e)
+
+	time.Sleep(40 * time.Millisecond)
+	mint.Emit(e, 4)
+	mint.Emit(e, 5) // dropped by off
+	<-off()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(got) != "[1 3 4]" {
+		t.Fatalf("expected leading and trailing values; got %v", got)
+	}
+}
+
+func TestOnThrottleClose(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	mint.OnThrottle(e, 10*time.Millisecond, func(v int) { got = append(got, v) })
+	mint.Emit(e, 1)
+	mint.Emit(e, 2) // dropped by Close
+	mint.Close(e)
+	mint.Drain(e)
+
+	if fmt.Sprint(got) != "[1]" {
+		t.Fatalf("expected trailing value to be dropped; got %v", got)
+	}
+}
+
+func TestEmitReverse(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got []string
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		mint.On(e, func(event) { got = append(got, name) })
+	}
+
+	mint.Emit(e, event{})
+	mint.EmitReverse(e, event{})
+
+	if fmt.Sprint(got) != "[a b c c b a]" {
+		t.Fatalf("expected consumers in reverse order; got %v", got)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	e := new(mint.Emitter)
+
+	t.Run("record", func(t *testing.T) {
+		r := minttest.Record[int](t, e)
+		go mint.Emit(e, 1)
+		if !r.Wait(1, time.Second) {
+			t.Fatal("expected a value to be recorded")
+		}
+
+		mint.Emit(e, 2)
+		if fmt.Sprint(r.Values()) != "[1 2]" {
+			t.Fatalf("expected %v; got %v", []int{1, 2}, r.Values())
+		}
+		if r.Wait(3, 10*time.Millisecond) {
+			t.Fatal("expected wait for more values to time out")
+		}
+	})
+
+	if c := mint.Count[int](e); c != 0 {
+		t.Fatalf("expected recorder to be unsubscribed after test; got %d consumers", c)
+	}
+}
+
+func TestOnDebug(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []string
+	mint.OnDebug(e, func(_ event, from runtime.Frame) {
+		got = append(got, strings.TrimPrefix(from.Function, "github.com/btvoidx/mint_test."))
+	})
+
+	mint.Emit(e, event{})
+	mint.SetCaptureCaller(e, true)
+	mint.Emit(e, event{})
+	ctxmint.Emit(e, context.Background(), event{})
+
+	if fmt.Sprintf("%q", got) != `["" "TestOnDebug" "TestOnDebug"]` {
+		t.Fatalf("expected emits to be traced to the test; got %q", got)
+	}
+}
+
+func TestEmitPooled(t *testing.T) {
+	e := new(mint.Emitter)
+
+	pool := sync.Pool{New: func() any { return new(int) }}
+	var got []int
+	mint.On(e, func(v *int) { got = append(got, *v) })
+
+	var put []*int
+	for i := 1; i <= 2; i++ {
+		i := i
+		mint.EmitPooled(e, func() *int {
+			v := pool.Get().(*int)
+			*v = i
+			return v
+		}, func(v *int) {
+			put = append(put, v)
+			pool.Put(v)
+		})
+	}
+
+	if fmt.Sprint(got) != "[1 2]" || len(put) != 2 {
+		t.Fatalf("expected values to be delivered and put back; got %v, put %d", got, len(put))
+	}
+}
+
+func TestRequest(t *testing.T) {
+	e := new(mint.Emitter)
+
+	type ask struct{ id, n int }
+	type answer struct{ id, n int }
+	mint.On(e, func(q ask) {
+		mint.Emit(e, answer{id: q.id + 1, n: -1}) // not a match
+		mint.Emit(e, answer{id: q.id, n: q.n * 2})
+	})
+
+	match := func(q ask, a answer) bool { return q.id == a.id }
+	a, err := mint.Request(e, context.Background(), ask{id: 1, n: 21}, match)
+	if err != nil || a.n != 42 {
+		t.Fatalf("expected %d; got %d, %v", 42, a.n, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := mint.Request(e, ctx, event{}, func(event, answer) bool { return true }); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v; got %v", context.DeadlineExceeded, err)
+	}
+
+	if c := mint.Count[answer](e); c != 0 {
+		t.Fatalf("expected response consumers to be unsubscribed; got %d", c)
+	}
+}
+
+func BenchmarkEmitDuringChurn(b *testing.B) {
+	e := new(mint.Emitter)
+	mint.On(e, func(int) {})
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				mint.OffSync(mint.On(e, func(string) {}))
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mint.Emit(e, 1)
+		}
+	})
+	b.StopTimer()
+
+	close(stop)
+	<-done
+}
+
+func TestEmitSafe(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	called := 0
+	mint.OnNamed(e, "bad", func(int) { panic("oops") })
+	mint.On(e, func(int) { called += 1 })
+	mint.OnE(e, func(int) error { return errors.New("not a panic") })
+	mint.On(e, func(int) { panic("oops again") })
+
+	panics := mint.EmitSafe(e, 1)
+	if called != 1 || len(panics) != 2 {
+		t.Fatalf("expected all consumers to be called and %d panics; got %d calls, %v", 2, called, panics)
+	}
+
+	var pe *mint.PanicError
+	if !errors.As(panics[0], &pe) || pe.Consumer.Name != "bad" || pe.Value != "oops" {
+		t.Fatalf("expected panic of consumer %q; got %v", "bad", panics[0])
+	}
+	if panics[1].Error() != "mint: consumer 4 of int panicked: oops again" {
+		t.Fatalf("unexpected error message: %v", panics[1])
+	}
+}
+
+func TestStats(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.OnNamed(e, "slow", func(int) { time.Sleep(time.Millisecond) })
+	mint.On(e, func(string) {})
+
+	mint.Emit(e, 1)
+	mint.SetStatsEnabled(e, true)
+	mint.Emit(e, 2)
+	mint.Emit(e, 3)
+	<-mint.EmitAsync(e, 4)
+
+	stats := mint.Stats(e)
+	if len(stats) != 2 {
+		t.Fatalf("expected stats of %d consumers; got %v", 2, stats)
+	}
+	if s := stats[0]; s.Name != "slow" || s.Calls != 3 || s.Total < 3*time.Millisecond {
+		t.Fatalf("expected 3 counted calls of slow consumer; got %+v", s)
+	}
+	if s := stats[1]; s.Calls != 0 || s.Total != 0 {
+		t.Fatalf("expected no calls of string consumer; got %+v", s)
+	}
+}
+
+func TestOffType(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got []string
+	mint.On(e, func(v int) { got = append(got, "declined") })
+	var h mint.Handle
+	h = mint.OnH(e, func(v int) {
+		got = append(got, "winner")
+		mint.OffType[int](e, h.ID())
+	})
+	mint.On(e, func(v int) { got = append(got, "skipped") })
+
+	mint.Emit(e, 1)
+	mint.Emit(e, 2)
+
+	if fmt.Sprint(got) != "[declined winner winner]" {
+		t.Fatalf("expected consumers after the winner to be skipped; got %v", got)
+	}
+
+	mint.OffType[int](e, 0)
+	if c := mint.Count[int](e); c != 0 {
+		t.Fatalf("expected %d consumers; got %d", 0, c)
+	}
+}
+
+func TestEmitR(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctxmint.On(e, func(context.Context, int) {})
+	ctxmint.On(e, func(context.Context, int) { cancel() })
+	ctxmint.On(e, func(context.Context, int) {})
+
+	r := ctxmint.EmitR(e, ctx, 1)
+	if r.Delivered != 2 || r.Total != 3 || r.Err != context.Canceled {
+		t.Fatalf("expected 2 of 3 consumers and %v; got %+v", context.Canceled, r)
+	}
+
+	if r := mint.EmitR(e, 2); r.Delivered != 3 || r.Total != 3 || r.Err != nil {
+		t.Fatalf("expected all 3 consumers; got %+v", r)
+	}
+}
+
+type order struct{ qty int }
+
+func (o order) Validate() error {
+	if o.qty <= 0 {
+		return errors.New("order must have positive quantity")
+	}
+	return nil
+}
+
+func TestEmitValidated(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	mint.On(e, func(o order) { got = append(got, o.qty) })
+	mint.On(e, func(v int) { got = append(got, v) })
+
+	if err := mint.EmitValidated(e, order{0}); err == nil {
+		t.Fatalf("expected invalid order to be rejected")
+	}
+	if err := ctxmint.EmitValidated(e, context.Background(), order{2}); err != nil {
+		t.Fatalf("expected valid order to be emitted; got %v", err)
+	}
+	if err := mint.EmitValidated(e, 3); err != nil {
+		t.Fatalf("expected non-validatable value to be emitted; got %v", err)
+	}
+
+	if !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Fatalf("expected [2 3]; got %v", got)
+	}
+}
+
+func TestWaiter(t *testing.T) {
+	e := new(mint.Emitter)
+
+	type success struct{ id int }
+	type failure struct{ err string }
+
+	w := mint.NewWaiter(e)
+	ok := mint.AddCase[success](w)
+	fail := mint.AddCase[failure](w)
+	if ok != 0 || fail != 1 {
+		t.Fatalf("expected cases 0 and 1; got %d and %d", ok, fail)
+	}
+
+	go func() {
+		for !mint.HasSubscribers[failure](e) {
+			time.Sleep(time.Millisecond)
+		}
+		mint.Emit(e, failure{"timeout"})
+		mint.Emit(e, success{1})
+	}()
+
+	i, v, err := w.Wait(context.Background())
+	if err != nil || i != fail || v != (failure{"timeout"}) {
+		t.Fatalf("expected case %d with %v; got %d with %v, %v", fail, failure{"timeout"}, i, v, err)
+	}
+
+	if mint.HasSubscribers[success](e) || mint.HasSubscribers[failure](e) {
+		t.Fatalf("expected consumers of all cases to be removed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if i, _, err := w.Wait(ctx); i != -1 || err != context.DeadlineExceeded {
+		t.Fatalf("expected -1 and %v; got %d, %v", context.DeadlineExceeded, i, err)
+	}
+}
+
+func TestUseOutcome(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var outcomes []error
+	mint.UseOutcome(e, func(any) func(error) {
+		return func(err error) { outcomes = append(outcomes, err) }
+	})
+	var plain int
+	mint.Use(e, func(any) func() { return func() { plain += 1 } })
+
+	errOdd := errors.New("odd")
+	mint.OnE(e, func(v int) error {
+		if v%2 == 1 {
+			return errOdd
+		}
+		return nil
+	})
+
+	mint.Emit(e, 1)
+	mint.Emit(e, 2)
+	<-mint.EmitAsync(e, 3)
+
+	if len(outcomes) != 3 || !errors.Is(outcomes[0], errOdd) || outcomes[1] != nil || !errors.Is(outcomes[2], errOdd) {
+		t.Fatalf("expected [%v <nil> %v]; got %v", errOdd, errOdd, outcomes)
+	}
+	if plain != 3 {
+		t.Fatalf("expected plain after-hook to be called 3 times; got %d", plain)
+	}
+}
+
+func TestSuppress(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got []int
+	mint.On(e, func(v int) { got = append(got, v) })
+	var names int
+	mint.On(e, func(string) { names += 1 })
+
+	outer := mint.Suppress[int](e)
+	inner := mint.Suppress[int](e)
+	mint.Emit(e, 1)
+	mint.Emit(e, "not suppressed")
+
+	inner()
+	inner()
+	mint.Emit(e, 2)
+
+	outer()
+	mint.Emit(e, 3)
+
+	if !reflect.DeepEqual(got, []int{3}) || names != 1 {
+		t.Fatalf("expected [3] and 1 string; got %v and %d", got, names)
+	}
+}
+
+func TestBindContext(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var got int
+	mint.On(e, func(int) { got += 1 })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := mint.BindContext(e, ctx)
+	mint.Emit(e, 1)
+	cancel()
+	<-done
+	mint.Emit(e, 2)
+
+	if got != 1 {
+		t.Fatalf("expected emits after cancel to do nothing; got %d values", got)
+	}
+	if err := mint.Close(e); err != mint.ErrClosed {
+		t.Fatalf("expected emitter to be closed; got %v", err)
+	}
+
+	e = new(mint.Emitter)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	done = mint.BindContext(e, ctx)
+	mint.Close(e)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected BindContext to give up on closed emitter")
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	e := new(mint.Emitter)
+
+	nop := func(any) func() { return nil }
+	mint.UseNamed(e, "metrics", nop)
+	mint.Use(e, nop)
+	mint.UseP(e, 1, nop)
+	unuse := mint.UseNamed(e, "auth", nop)
+
+	expected := []string{"plugin#1", "metrics", "plugin#3", "auth"}
+	if got := mint.Plugins(e); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v; got %v", expected, got)
+	}
+
+	<-unuse()
+	expected = []string{"plugin#1", "metrics", "plugin#3"}
+	if got := mint.Plugins(e); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v; got %v", expected, got)
+	}
+}
+
+func TestSetDeadLetter(t *testing.T) {
+	e := new(mint.Emitter)
+
+	dead := make(chan string, 8)
+	mint.SetDeadLetter(e, 8, func(typeName string, v any) {
+		dead <- fmt.Sprintf("%s:%v", typeName, v)
+	})
+
+	_, off := mint.OnChan[int](e, 1, mint.DropNewest)
+	defer off()
+	mint.Emit(e, 1)
+	mint.Emit(e, 2)
+
+	resume := mint.Suppress[string](e)
+	mint.Emit(e, "muted")
+	resume()
+
+	mint.SetMaxFanout(e, 1)
+	mint.On(e, func(bool) {})
+	mint.On(e, func(bool) {})
+	if err := mint.EmitStrict(e, true); !errors.Is(err, mint.ErrFanout) {
+		t.Fatalf("expected %v; got %v", mint.ErrFanout, err)
+	}
+
+	expected := []string{"int:2", "string:muted", "bool:true"}
+	for _, want := range expected {
+		select {
+		case got := <-dead:
+			if got != want {
+				t.Fatalf("expected %v; got %v", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %v to be passed to dead letter handler", want)
+		}
+	}
+}
+
+func TestDeadLetterLost(t *testing.T) {
+	e := new(mint.Emitter)
+
+	release := make(chan struct{})
+	mint.SetDeadLetter(e, 1, func(string, any) { <-release })
+	defer mint.Close(e)
+	defer close(release)
+
+	resume := mint.Suppress[int](e)
+	defer resume()
+	for i := 0; i < 10; i++ {
+		mint.Emit(e, i)
+	}
+
+	// one value is being handled and one is buffered
+	if lost := mint.DeadLetterLost(e); lost != 8 && lost != 9 {
+		t.Fatalf("expected 8 or 9 values to be lost; got %d", lost)
+	}
+}
+
+func TestEmitCancelable(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mint.On(e, func(v int) {
+		got.Add(1)
+		if v == 1 {
+			close(started)
+			<-release
+		}
+	})
+	mint.On(e, func(int) { got.Add(1) })
+
+	wait, cancel := mint.EmitCancelable(e, 1)
+	<-started
+	cancel()
+	close(release)
+
+	if err := wait(); err != context.Canceled {
+		t.Fatalf("expected %v; got %v", context.Canceled, err)
+	}
+	if got.Load() != 1 {
+		t.Fatalf("expected 1 consumer to be called; got %d", got.Load())
+	}
+
+	wait, cancel = mint.EmitCancelable(e, 2)
+	defer cancel()
+	if err := wait(); err != nil || got.Load() != 3 {
+		t.Fatalf("expected all consumers to be called; got %d, %v", got.Load(), err)
+	}
+}
+
+func TestTypeNames(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.On(e, func(event) {})
+	mint.On(e, func(event) {})
+	mint.On(e, func(error) {})
+	mint.OnAll(e, func(any) {})
+	off := mint.On(e, func(int) {})
+	<-off()
+
+	expected := []string{"error", "mint_test.event"}
+	if got := mint.TypeNames(e); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v; got %v", expected, got)
+	}
+}
+
+type lookupError struct{ key string }
+
+func (err *lookupError) Error() string { return "no " + err.key }
+
+func TestOnError(t *testing.T) {
+	e := new(mint.Emitter)
+
+	var all []string
+	off := mint.OnError(e, func(err error) { all = append(all, err.Error()) })
+	var typed []string
+	mint.On(e, func(err *lookupError) { typed = append(typed, err.key) })
+
+	mint.Emit(e, &lookupError{"user"})
+	mint.EmitError(e, errors.New("disk full"))
+	mint.EmitError(e, nil)
+	mint.Emit(e, 1)
+	<-mint.EmitAsync(e, &lookupError{"order"})
+
+	expected := []string{"no user", "disk full", "no order"}
+	if !reflect.DeepEqual(all, expected) {
+		t.Fatalf("expected %v; got %v", expected, all)
+	}
+	if !reflect.DeepEqual(typed, []string{"user", "order"}) {
+		t.Fatalf("expected typed consumer to receive [user order]; got %v", typed)
+	}
+
+	<-off()
+	mint.Emit(e, &lookupError{"item"})
+	if len(all) != 3 {
+		t.Fatalf("expected no errors after off; got %v", all)
+	}
+}
+
+func TestOnErrorIsNotTyped(t *testing.T) {
+	e := new(mint.Emitter)
+
+	mint.OnError(e, func(error) {})
+	if names := mint.TypeNames(e); len(names) != 0 {
+		t.Fatalf("expected no types; got %v", names)
+	}
+	if cs := mint.Consumers(e); len(cs) != 0 {
+		t.Fatalf("expected no consumers; got %v", cs)
+	}
+
+	var seqs []uint64
+	mint.OnSeq(e, func(seq uint64, _ int) { seqs = append(seqs, seq) })
+	mint.Emit(e, 1)
+	mint.EmitError(e, errors.New("oops"))
+	mint.Emit(e, 2)
+	if !reflect.DeepEqual(seqs, []uint64{1, 3}) {
+		t.Fatalf("expected streamed error not to take a number; got %v", seqs)
+	}
+}
+
+func TestRawOn(t *testing.T) {
+	e := mint.NewOrderedEmitter()
+
+	var got []string
+	mint.RawOn(e, reflect.TypeOf(0), func(v any) { got = append(got, fmt.Sprint("raw", v.(int))) })
+	mint.On(e, func(v int) { got = append(got, fmt.Sprint("typed", v)) })
+
+	mint.Emit(e, 1)
+	if err := mint.RawEmit(e, reflect.TypeOf(0), 2); err != nil {
+		t.Fatalf("expected no error; got %v", err)
+	}
+	if err := mint.RawEmit(e, reflect.TypeOf(0), "3"); !errors.Is(err, mint.ErrTypeMismatch) {
+		t.Fatalf("expected %v; got %v", mint.ErrTypeMismatch, err)
+	}
+
+	expected := []string{"raw1", "typed1", "raw2", "typed2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v; got %v", expected, got)
+	}
+}
+
+func TestOnWithReplay(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.WithHistory[int](e, 3)
+
+	for i := 1; i <= 4; i++ {
+		mint.Emit(e, i)
+	}
+
+	var got []int
+	mint.OnWithReplay(e, 2, func(v int) { got = append(got, v) })
+	mint.Emit(e, 5)
+
+	if !reflect.DeepEqual(got, []int{3, 4, 5}) {
+		t.Fatalf("expected %v; got %v", []int{3, 4, 5}, got)
+	}
+}
+
+func TestOnWithReplayAfterPluginPanic(t *testing.T) {
+	e := new(mint.Emitter)
+	mint.WithHistory[int](e, 2)
+
+	mint.Emit(e, 1)
+	unuse := mint.UseTransform(e, func(any) any { return "not an int" })
+	func() {
+		defer func() { _ = recover() }()
+		mint.EmitAsync(e, 2)
+		t.Fatalf("expected EmitAsync to panic")
+	}()
+	<-unuse()
+
+	got := make(chan []int, 1)
+	go func() {
+		var vs []int
+		mint.OnWithReplay(e, 2, func(v int) { vs = append(vs, v) })
+		got <- vs
+	}()
+
+	select {
+	case vs := <-got:
+		if !reflect.DeepEqual(vs, []int{1}) {
+			t.Fatalf("expected %v; got %v", []int{1}, vs)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected OnWithReplay not to wait for panicked emit")
+	}
+}
+
+func TestOnWithReplayBoundary(t *testing.T) {
+	const n = 2000
+
+	for attempt := 0; attempt < 20; attempt++ {
+		e := new(mint.Emitter)
+		mint.WithHistory[int](e, n)
+		mint.UseTransform(e, func(v any) any { runtime.Gosched(); return v })
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 1; i <= n; i++ {
+				mint.Emit(e, i)
+			}
+		}()
+
+		// subscribe once emits are under way
+		for seen := false; !seen; runtime.Gosched() {
+			mint.Replay(e, func(int) { seen = true })
+		}
+
+		var mu sync.Mutex
+		var got []int
+		mint.OnWithReplay(e, n, func(v int) {
+			mu.Lock()
+			got = append(got, v)
+			mu.Unlock()
+		})
+		<-done
+
+		mu.Lock()
+		if len(got) != n {
+			t.Fatalf("expected %d values; got %d", n, len(got))
+		}
+		for i, v := range got {
+			if v != i+1 {
+				t.Fatalf("expected values in order without gaps or duplicates; got %d at %d", v, i)
+			}
+		}
+		mu.Unlock()
+	}
+}
@@ -0,0 +1,69 @@
+// Package minttest provides helpers for testing code built on mint.
+//
+//	r := minttest.Record[MyEvent](t, e) // record MyEvent values until t ends
+//	doSomething(e)
+//	if !r.Wait(1, time.Second) { t.Fatal("MyEvent was not emitted") }
+package minttest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btvoidx/mint"
+)
+
+// Recorder collects values received by a consumer. It is safe
+// for concurrent use.
+type Recorder[T any] struct {
+	mu      sync.Mutex
+	values  []T
+	changed chan struct{} // closed and replaced once a value is added
+}
+
+// Record registers a consumer of T with e, which records all values it
+// receives until tb and its subtests finish, when it is unsubscribed.
+func Record[T any](tb testing.TB, e *mint.Emitter) *Recorder[T] {
+	tb.Helper()
+
+	r := &Recorder[T]{changed: make(chan struct{})}
+	off := mint.On(e, func(v T) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.values = append(r.values, v)
+		close(r.changed)
+		r.changed = make(chan struct{})
+	})
+	tb.Cleanup(func() { mint.OffSync(off) })
+	return r
+}
+
+// Values returns values recorded so far, in order they were received.
+func (r *Recorder[T]) Values() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]T(nil), r.values...)
+}
+
+// Wait waits up to timeout for at least n values to be recorded,
+// and reports whether they were.
+func (r *Recorder[T]) Wait(n int, timeout time.Duration) bool {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	for {
+		r.mu.Lock()
+		got, changed := len(r.values), r.changed
+		r.mu.Unlock()
+
+		if got >= n {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-t.C:
+			return false
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// EmitOne works like Emit, but pushes v to only one consumer of T, so that
+// Emitter can hand out work to its consumers. Consumers take turns in
+// order they were registered in, regardless of their priority, with each
+// Emit picking the one after the consumer picked by the previous one.
+// It reports whether there was a consumer to push v to.
+// EmitOne is not affected by Pause, as it has to report it right away.
+func EmitOne[T any](e *Emitter, v T) (delivered bool) {
+	delivered, _ = cm.EmitOne(e, context.Background(), v)
+	return delivered
+}
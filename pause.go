@@ -0,0 +1,21 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// Pause makes following emits through e queue values instead of delivering
+// them, until Resume is called. Queued emits return right away as if there
+// were no consumers, except for EmitAsync and EmitPool, which return chans
+// that get closed once their values are delivered after Resume.
+//
+// Pausing a paused Emitter does nothing.
+func Pause(e *Emitter) {
+	cm.Pause(e)
+}
+
+// Resume delivers values queued since Pause in order they were emitted
+// and returns to live delivery. Values emitted while they are delivered,
+// including ones emitted by consumers, are queued as well and delivered
+// after them. Resume returns once the queue is empty.
+func Resume(e *Emitter) {
+	cm.Resume(e)
+}
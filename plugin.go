@@ -0,0 +1,80 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// Use allows to hook into event emitting process. Plugins are
+// called sequentially in order they were added to Emitter.
+// Plugin is a function that takes Emitted values and
+// returns nil or a function that will be called after
+// all consumers got the Emitted value. Returned functions
+// are called in reverse order via `defer` statement, so that
+// the first plugin's function is called last. Plugins which
+// return nil are skipped without affecting that order.
+// Use with nil Emitter, as well as its variants, installs nothing.
+//
+// Call to unuse removes plugin before returning and returns a chan which
+// is already closed. Emits which are already in progress may still call it.
+func Use(e *Emitter, plugin func(any) func()) (unuse func() <-chan struct{}) {
+	return cm.Use(e, func(_ context.Context, v any) func() { return plugin(v) })
+}
+
+// UseP installs a plugin like Use, but with given priority. Plugins with
+// higher priority are called before those with lower one, regardless of
+// when they were installed, while ones with equal priority are called in
+// order they were installed in. Plugins installed with Use and its other
+// variants have priority of 0, so UseP with a positive priority puts
+// a plugin in front of them, and with a negative one after them.
+func UseP(e *Emitter, priority int, plugin func(any) func()) (unuse func() <-chan struct{}) {
+	return cm.UseP(e, priority, func(_ context.Context, v any) func() { return plugin(v) })
+}
+
+// UseNamed installs a plugin like Use, but with given name, which is
+// listed by Plugins, so that plugins can be told apart.
+func UseNamed(e *Emitter, name string, plugin func(any) func()) (unuse func() <-chan struct{}) {
+	return cm.UseNamed(e, name, func(_ context.Context, v any) func() { return plugin(v) })
+}
+
+// Plugins returns names of all currently installed plugins, in order
+// they are called in. Plugins installed without a name are listed as
+// "plugin#N", where N is their position in the list, starting from 1.
+func Plugins(e *Emitter) []string {
+	return cm.Plugins(e)
+}
+
+// UseTransform installs a plugin like Use, which replaces emitted values with
+// ones returned by fn, so that consumers and plugins added after it
+// receive the replaced value. It is called in order with other plugins.
+//
+// Consumers are still picked by the type Emit was called with, so fn
+// must return a value of that type, otherwise Emit panics.
+func UseTransform(e *Emitter, fn func(v any) any) (unuse func() <-chan struct{}) {
+	return cm.UseTransform(e, func(_ context.Context, v any) any { return fn(v) })
+}
+
+// UseGuard installs a plugin like Use, which can stop values from being emitted.
+// If fn returns an error, plugins after it and consumers are not called.
+// The error is returned by EmitErr. Functions returned by plugins before
+// the guard are still called. It is called in order with other plugins.
+func UseGuard(e *Emitter, fn func(v any) error) (unuse func() <-chan struct{}) {
+	return cm.UseGuard(e, func(_ context.Context, v any) error { return fn(v) })
+}
+
+// UseOutcome installs a plugin like Use, but functions returned by fn
+// receive outcome of the emit: errors returned by consumers registered
+// with OnE joined using errors.Join, or an error returned by a guard
+// plugin after it. They receive nil if emit went fine.
+// It is called in order with other plugins.
+func UseOutcome(e *Emitter, fn func(v any) func(err error)) (unuse func() <-chan struct{}) {
+	return cm.UseOutcome(e, func(_ context.Context, v any) func(error) { return fn(v) })
+}
+
+// UseFor installs a plugin like Use, which is only called for emits of T
+// and receives values as T. It is called in order with other plugins,
+// including ones installed with Use.
+func UseFor[T any](e *Emitter, fn func(T) func()) (unuse func() <-chan struct{}) {
+	return cm.UseFor(e, func(_ context.Context, v T) func() { return fn(v) })
+}
@@ -0,0 +1,19 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// EmitPooled works like Emit, but takes the value to emit from get and
+// hands it to put once all consumers returned, so that values can be
+// reused, such as with sync.Pool. Values queued by Pause or serial
+// Emitters are handed to put once they are delivered.
+//
+// Consumers must not retain the value after they return. This rules out
+// consumers which keep it for later, like ones registered with OnChan,
+// OnQueue, OnDebounce or OnThrottle, as well as sticky types and history.
+func EmitPooled[T any](e *Emitter, get func() T, put func(T)) {
+	fanout(cm.EmitPooled(e, context.Background(), get, put))
+}
@@ -0,0 +1,21 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnQueue registers a new consumer like On, but fn is called from its own
+// goroutine, which takes values from a queue of given size in order they
+// were emitted, so that Emits don't wait for fn to return. Once the queue
+// is full, policy decides what happens to further values. Sizes below 1
+// are treated as 1.
+//
+// Call to off unsubscribes and returns a chan which will get closed once
+// fn returns for all values which were already queued. Calling OffSync
+// from within fn blocks forever. Closing e calls off, so the goroutine
+// doesn't outlive it.
+func OnQueue[T any](e *Emitter, size int, policy Overflow, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnQueue(e, size, policy, func(_ context.Context, v T) { fn(v) })
+}
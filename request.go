@@ -0,0 +1,19 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// Request emits req and waits for the first value emitted as Resp for
+// which match returns true, and returns it. The consumer waiting for
+// the response is registered before req is emitted, so responses which
+// are emitted by consumers of req are not missed, and it is always
+// unsubscribed before Request returns.
+//
+// If ctx is cancelled before a matching response, zero value
+// and ctx.Err() are returned.
+func Request[Req, Resp any](e *Emitter, ctx context.Context, req Req, match func(Req, Resp) bool) (Resp, error) {
+	return cm.Request(e, ctx, req, match)
+}
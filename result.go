@@ -0,0 +1,18 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// EmitResult describes how far an emit got.
+type EmitResult = cm.EmitResult
+
+// EmitR works like Emit, but also reports how many consumers were called
+// out of how many there were, along with an error returned by a guard
+// plugin, or ErrClosed if e is closed. Values queued by Pause or serial
+// Emitters report zero consumers.
+func EmitR[T any](e *Emitter, v T) EmitResult {
+	return cm.EmitR(e, context.Background(), v)
+}
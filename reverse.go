@@ -0,0 +1,16 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// EmitReverse works like Emit, but calls consumers in reverse of the
+// order Emit calls them in, so that consumers with lower priority are
+// called first, and ones with equal priority are called in reverse
+// order of registration if the Emitter is ordered. This lets consumers
+// which were set up first be torn down last.
+func EmitReverse[T any](e *Emitter, v T) {
+	fanout(cm.EmitReverse(e, context.Background(), v))
+}
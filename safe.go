@@ -0,0 +1,21 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// PanicError is a panic of a consumer, recovered by EmitSafe.
+type PanicError = cm.PanicError
+
+// EmitSafe works like Emit, but recovers panics of consumers and returns
+// them as *PanicError, in order consumers were called in, so that one
+// consumer which panics doesn't stop others from being called. Unlike
+// EmitErr, errors returned by consumers are discarded. The panic handler
+// is not called for recovered panics.
+func EmitSafe[T any](e *Emitter, v T) []error {
+	panics, err := cm.EmitSafe(e, context.Background(), v)
+	fanout(err)
+	return panics
+}
@@ -0,0 +1,16 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnSeq registers a new consumer like On, which also receives sequence
+// number of the emit. Once e has a consumer registered with OnSeq, every
+// emit through e, regardless of its type, gets a number one greater than
+// the emit before it, starting from 1. Concurrent emits get distinct
+// numbers, in order they started in.
+func OnSeq[T any](e *Emitter, fn func(seq uint64, v T)) (off func() <-chan struct{}) {
+	return cm.OnSeq(e, func(_ context.Context, seq uint64, v T) { fn(seq, v) })
+}
@@ -0,0 +1,18 @@
+package mint
+
+import (
+	"time"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// SetSlowConsumerHandler makes Emit measure how long each consumer takes
+// and report ones which take longer than d to h, after they return. It
+// doesn't affect delivery, but h is called synchronously by Emit, so it
+// should return quickly. Consumers called by EmitAsync and EmitPool are
+// not measured.
+//
+// Using nil h stops reporting, which is the default.
+func SetSlowConsumerHandler(e *Emitter, d time.Duration, h func(c Consumer, elapsed time.Duration)) {
+	cm.SetSlowConsumerHandler(e, d, h)
+}
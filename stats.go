@@ -0,0 +1,21 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// SubStat is how many times a consumer was called and how long it took.
+type SubStat = cm.SubStat
+
+// SetStatsEnabled makes emits through e count calls of each consumer
+// and time spent in them, which Stats reports. Emits which are already
+// in progress may not be counted. Stats are disabled by default, as
+// timing consumers makes emits slower.
+func SetStatsEnabled(e *Emitter, on bool) {
+	cm.SetStatsEnabled(e, on)
+}
+
+// Stats returns stats of all currently registered consumers,
+// in order they were registered in. Calls made while stats
+// were disabled are not counted.
+func Stats(e *Emitter) []SubStat {
+	return cm.Stats(e)
+}
@@ -0,0 +1,62 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// Sticky makes Emitter retain the last value emitted as T, so that
+// consumers registered with OnSticky receive it right away.
+//
+// Each sticky type keeps its last value for as long as the Emitter lives,
+// unless it is dropped with Clear or ClearType. Types with history
+// configured by WithHistory are already sticky.
+func Sticky[T any](e *Emitter) {
+	cm.Sticky[T](e)
+}
+
+// WithHistory makes Emitter retain up to n last values emitted as T,
+// evicting the oldest ones once there are more. Retained values can
+// be iterated with Replay. Using n <= 0 stops retaining values of T.
+//
+// Changing n of a type which already has history keeps the latest values.
+// Types with history are sticky.
+func WithHistory[T any](e *Emitter, n int) {
+	cm.WithHistory[T](e, n)
+}
+
+// Replay calls fn with values retained for T, oldest first.
+func Replay[T any](e *Emitter, fn func(T)) {
+	cm.Replay(e, fn)
+}
+
+// OnSticky registers a new consumer like On. If T is sticky and a value
+// was emitted as T before, fn is called with it before OnSticky returns.
+// Nothing is replayed if no value was emitted yet.
+//
+// A value emitted concurrently with the call may be delivered
+// before the replayed one.
+func OnSticky[T any](e *Emitter, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnSticky(e, func(_ context.Context, v T) { fn(v) })
+}
+
+// OnWithReplay registers a new consumer like On, but first calls fn with
+// up to k latest values retained for T, oldest first, before returning.
+// T must be sticky or have history configured by WithHistory, otherwise
+// nothing is replayed.
+//
+// Replayed values and live ones never overlap and leave no gap between
+// them: values of emits which started before OnWithReplay are the ones
+// replayed, waiting for them to be retained if they are still passing
+// through plugins, while values of emits which start after it are
+// delivered live. Live values which arrive during replay are held until it is over
+// and then passed to fn from OnWithReplay, so fn receives no live value
+// before all replayed ones.
+//
+// As it waits for emits in progress, calling OnWithReplay of T from
+// plugins, or from consumers of values emitted as T with EmitAll,
+// blocks forever.
+func OnWithReplay[T any](e *Emitter, k int, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnWithReplay(e, k, func(_ context.Context, v T) { fn(v) })
+}
@@ -0,0 +1,15 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnStop registers a new consumer like On, but fn also receives stop,
+// calling which makes the Emit skip remaining consumers and return once
+// fn does. Emit doesn't report it as an error. EmitAsync, EmitPool and
+// EmitGroup, which call consumers concurrently, ignore stop.
+func OnStop[T any](e *Emitter, fn func(v T, stop func())) (off func() <-chan struct{}) {
+	return cm.OnStop(e, func(_ context.Context, v T, stop func()) { fn(v, stop) })
+}
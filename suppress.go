@@ -0,0 +1,14 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// Suppress makes following emits of T through e discard their values,
+// without calling plugins or consumers, until resume is called. Unlike
+// Pause, it only affects emits of T, and values are not delivered later.
+// Emits of T which are already in progress are not affected.
+//
+// Suppressing T more than once keeps it suppressed until all of returned
+// resumes are called. Calling resume more than once does nothing.
+func Suppress[T any](e *Emitter) (resume func()) {
+	return cm.Suppress[T](e)
+}
@@ -0,0 +1,16 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// Tap installs fn to observe all values emitted through e along with
+// name of the type they were emitted as. fn is called after plugins,
+// with the value consumers receive, before any of them are called.
+// Values stopped by a guard plugin are not observed.
+//
+// Unlike plugins, fn can't affect the emit. It is called synchronously,
+// so it should return quickly.
+//
+// Call to untap works like unuse returned by Use.
+func Tap(e *Emitter, fn func(typeName string, v any)) (untap func() <-chan struct{}) {
+	return cm.Tap(e, fn)
+}
@@ -0,0 +1,22 @@
+package mint
+
+import (
+	"context"
+	"time"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// OnThrottle registers a new consumer like On, but fn is called at most
+// once per d. A value emitted while no interval is running is passed to fn
+// right away and starts one. Values emitted during the interval are dropped,
+// except for the last of them, which is passed to fn from its own goroutine
+// once the interval ends, starting another one. Drain waits for trailing
+// values to be passed to fn.
+//
+// Call to off unsubscribes, drops the trailing value, if any, and returns
+// a chan which will get closed once fn returns for it, if it is running.
+// Closing e drops the trailing value as well.
+func OnThrottle[T any](e *Emitter, d time.Duration, fn func(T)) (off func() <-chan struct{}) {
+	return cm.OnThrottle(e, d, func(_ context.Context, v T) { fn(v) })
+}
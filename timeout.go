@@ -0,0 +1,22 @@
+package mint
+
+import (
+	"context"
+	"time"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// ErrTimeout is reported for consumers that took too long to return.
+var ErrTimeout = cm.ErrTimeout
+
+// EmitTimeout works like EmitErr, but gives each consumer d to return.
+// A consumer that takes longer is left running in its own goroutine and
+// emitting continues with the next one. Returned error joins an error
+// wrapping ErrTimeout for every such consumer with errors of the others.
+//
+// Panics of a consumer which timed out can only be recovered by
+// a panic handler and are discarded otherwise.
+func EmitTimeout[T any](e *Emitter, v T, d time.Duration) error {
+	return cm.EmitTimeout(e, context.Background(), v, d)
+}
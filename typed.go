@@ -0,0 +1,85 @@
+package mint
+
+import "sync"
+
+// TypedEmitter is an emitter of a single type T. It is lighter than
+// Emitter, as consumers are stored and called without type assertions.
+// Zero value is ready to use.
+//
+// TypedEmitter doesn't support plugins or any of the other Emitter options.
+type TypedEmitter[T any] struct {
+	subc uint64
+	subs map[uint64]func(T)
+	// subs as a slice, rebuilt whenever they change,
+	// so that emits can call them without holding the lock
+	list []func(T)
+
+	mu sync.RWMutex
+}
+
+// Emit Sequentially pushes value v to all consumers.
+// Receive order is indetermenistic. Consumers are free to call On
+// or off, as they are called without holding the TypedEmitter.
+func (e *TypedEmitter[T]) Emit(v T) {
+	e.mu.RLock()
+	list := e.list
+	e.mu.RUnlock()
+
+	for _, fn := range list {
+		fn(v)
+	}
+}
+
+// On Registers a new consumer that receives all emitted values.
+//
+// Call to off works like off returned by On, except that emits which
+// are already in progress may still call the consumer.
+func (e *TypedEmitter[T]) On(fn func(T)) (off func() <-chan struct{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.subs == nil {
+		e.subs = make(map[uint64]func(T))
+	}
+
+	id := e.subc
+	e.subc += 1
+	e.subs[id] = fn
+	e.relist()
+
+	var once sync.Once
+	return func() <-chan struct{} {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+
+			delete(e.subs, id)
+			e.relist()
+		})
+		return closedChan
+	}
+}
+
+// relist rebuilds e.list. It is never modified in place, as emits
+// keep using it after releasing the lock. Caller must hold e.mu.
+func (e *TypedEmitter[T]) relist() {
+	list := make([]func(T), 0, len(e.subs))
+	for _, fn := range e.subs {
+		list = append(list, fn)
+	}
+	e.list = list
+}
+
+// Count returns the number of consumers currently registered.
+func (e *TypedEmitter[T]) Count() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.subs)
+}
+
+// closedChan is a chan which is always closed.
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
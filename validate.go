@@ -0,0 +1,19 @@
+package mint
+
+import (
+	"context"
+
+	cm "github.com/btvoidx/mint/context"
+)
+
+// Validator is implemented by values which can tell whether they are
+// well-formed, to be checked by EmitValidated before they are emitted.
+type Validator = cm.Validator
+
+// EmitValidated works like Emit, but if v implements Validator, calls its
+// Validate method first. If it returns an error, no plugins or consumers
+// are called and EmitValidated returns that error. Otherwise it returns
+// ErrClosed if e is closed, or an error returned by a guard plugin.
+func EmitValidated[T any](e *Emitter, v T) error {
+	return cm.EmitValidated(e, context.Background(), v)
+}
@@ -0,0 +1,21 @@
+package mint
+
+import cm "github.com/btvoidx/mint/context"
+
+// Waiter waits for the first of values of several types to be emitted,
+// like a select statement over events. Cases are added with AddCase,
+// and Wait blocks until one of them fires.
+// The zero Waiter is not usable, use NewWaiter instead.
+type Waiter = cm.Waiter
+
+// NewWaiter returns a Waiter for values emitted with e.
+func NewWaiter(e *Emitter) *Waiter {
+	return cm.NewWaiter(e)
+}
+
+// AddCase adds a case for values emitted as T to w and returns its index,
+// which Wait returns once the case fires. Cases must not be added
+// during a call to Wait.
+func AddCase[T any](w *Waiter) (index int) {
+	return cm.AddCase[T](w)
+}